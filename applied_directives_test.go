@@ -0,0 +1,216 @@
+package graphql_test
+
+import (
+	"reflect"
+	"strings"
+	"testing"
+
+	"github.com/dagger/graphql"
+	"github.com/dagger/graphql/testutil"
+)
+
+const appliedDirectivesSDL = `
+directive @key(fields: String!) repeatable on OBJECT
+
+directive @policy(level: Level!) on ARGUMENT_DEFINITION | ENUM_VALUE | FIELD_DEFINITION | INPUT_FIELD_DEFINITION
+
+enum Level {
+  ADMIN
+  PUBLIC
+}
+
+type Query {
+  user(id: ID @policy(level: PUBLIC)): User
+}
+
+type User @key(fields: "id") @key(fields: "email") {
+  email: String @policy(level: ADMIN)
+  id: ID
+}
+
+input UserFilter {
+  name: String @policy(level: PUBLIC)
+}
+
+enum Visibility {
+  HIDDEN @policy(level: ADMIN)
+  VISIBLE
+}
+`
+
+func TestAppliedDirectives_AreReadableFromTheSchema(t *testing.T) {
+	schema, err := graphql.BuildSchema(appliedDirectivesSDL, graphql.BuildSchemaOptions{})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	user := schema.Type("User").(*graphql.Object)
+	expected := []*graphql.AppliedDirective{
+		{Name: "key", Args: map[string]any{"fields": "id"}},
+		{Name: "key", Args: map[string]any{"fields": "email"}},
+	}
+	if !reflect.DeepEqual(expected, user.AppliedDirectives()) {
+		t.Fatalf("Unexpected result, Diff: %v", testutil.Diff(expected, user.AppliedDirectives()))
+	}
+
+	admin := []*graphql.AppliedDirective{{Name: "policy", Args: map[string]any{"level": "ADMIN"}}}
+	public := []*graphql.AppliedDirective{{Name: "policy", Args: map[string]any{"level": "PUBLIC"}}}
+	if got := user.Fields()["email"].AppliedDirectives; !reflect.DeepEqual(admin, got) {
+		t.Fatalf("Unexpected field directives, Diff: %v", testutil.Diff(admin, got))
+	}
+	if got := schema.QueryType().Fields()["user"].Args[0].AppliedDirectives; !reflect.DeepEqual(public, got) {
+		t.Fatalf("Unexpected argument directives, Diff: %v", testutil.Diff(public, got))
+	}
+	if got := schema.Type("UserFilter").(*graphql.InputObject).Fields()["name"].AppliedDirectives; !reflect.DeepEqual(public, got) {
+		t.Fatalf("Unexpected input field directives, Diff: %v", testutil.Diff(public, got))
+	}
+	for _, value := range schema.Type("Visibility").(*graphql.Enum).Values() {
+		if value.Name == "HIDDEN" && !reflect.DeepEqual(admin, value.AppliedDirectives) {
+			t.Fatalf("Unexpected enum value directives, Diff: %v", testutil.Diff(admin, value.AppliedDirectives))
+		}
+	}
+}
+
+func TestAppliedDirectives_AreReadableWhileResolving(t *testing.T) {
+	queryType := graphql.NewObject(graphql.ObjectConfig{
+		Name: "Query",
+		Fields: graphql.Fields{
+			"secret": &graphql.Field{
+				Type: graphql.String,
+				AppliedDirectives: []*graphql.AppliedDirective{
+					{Name: "policy", Args: map[string]any{"level": "ADMIN"}},
+				},
+				Resolve: func(p graphql.ResolveParams) (any, error) {
+					field := p.Info.ParentType.(*graphql.Object).Fields()[p.Info.FieldName]
+					for _, directive := range field.AppliedDirectives {
+						if directive.Name == "policy" {
+							return "level " + directive.Args["level"].(string), nil
+						}
+					}
+					return nil, nil
+				},
+			},
+		},
+	})
+	schema, err := graphql.NewSchema(graphql.SchemaConfig{Query: queryType})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	result := graphql.Do(graphql.Params{
+		Schema:        schema,
+		RequestString: `{ secret }`,
+	})
+	expected := &graphql.Result{
+		Data: map[string]any{
+			"secret": "level ADMIN",
+		},
+	}
+	if !reflect.DeepEqual(expected, result) {
+		t.Fatalf("Unexpected result, Diff: %v", testutil.Diff(expected, result))
+	}
+}
+
+func TestAppliedDirectives_ArePrinted(t *testing.T) {
+	schema, err := graphql.BuildSchema(appliedDirectivesSDL, graphql.BuildSchemaOptions{})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	printed := graphql.PrintSchema(&schema)
+	expected := strings.TrimPrefix(appliedDirectivesSDL, "\n")
+	if printed != expected {
+		t.Fatalf("Unexpected result, Diff: %v", testutil.Diff(expected, printed))
+	}
+}
+
+func TestAppliedDirectives_IntrospectsIsRepeatable(t *testing.T) {
+	schema, err := graphql.BuildSchema(appliedDirectivesSDL, graphql.BuildSchemaOptions{})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	result := graphql.Do(graphql.Params{
+		Schema:        schema,
+		RequestString: `{ __schema { directives { name isRepeatable } } }`,
+	})
+	expected := map[string]bool{
+		"key":        true,
+		"policy":     false,
+		"include":    false,
+		"skip":       false,
+		"deprecated": false,
+		"oneOf":      false,
+	}
+	if len(result.Errors) > 0 {
+		t.Fatalf("unexpected errors: %v", result.Errors)
+	}
+	directives := result.Data.(map[string]any)["__schema"].(map[string]any)["directives"].([]any)
+	if len(directives) != len(expected) {
+		t.Fatalf("unexpected directives: %v", directives)
+	}
+	for _, directive := range directives {
+		directive := directive.(map[string]any)
+		if repeatable := expected[directive["name"].(string)]; directive["isRepeatable"] != repeatable {
+			t.Fatalf("expected isRepeatable of %v to be %v", directive["name"], repeatable)
+		}
+	}
+}
+
+func TestAppliedDirectives_RepeatableDirectivesMayBeRepeated(t *testing.T) {
+	repeatable := graphql.NewDirective(graphql.DirectiveConfig{
+		Name:         "tag",
+		Locations:    []string{graphql.DirectiveLocationField},
+		IsRepeatable: true,
+		Args: graphql.FieldConfigArgument{
+			&graphql.ArgumentConfig{Name: "name", Type: graphql.String},
+		},
+	})
+	schema, err := graphql.NewSchema(graphql.SchemaConfig{
+		Query: graphql.NewObject(graphql.ObjectConfig{
+			Name: "Query",
+			Fields: graphql.Fields{
+				"a": &graphql.Field{Type: graphql.String},
+			},
+		}),
+		Directives: append([]*graphql.Directive{repeatable}, graphql.SpecifiedDirectives...),
+	})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	result := graphql.Do(graphql.Params{
+		Schema:        schema,
+		RequestString: `{ a @tag(name: "x") @tag(name: "y") @skip(if: false) @skip(if: false) }`,
+	})
+	expectedErrors := []string{`The directive "@skip" can only be used once at this location.`}
+	if len(result.Errors) != 1 || result.Errors[0].Message != expectedErrors[0] {
+		t.Fatalf("expected errors %v, got: %v", expectedErrors, result.Errors)
+	}
+}
+
+type appliedDirectiveKey string
+
+func TestAppliedDirectives_PrintsArgumentsOfUnknownDirectives(t *testing.T) {
+	schema, err := graphql.NewSchema(graphql.SchemaConfig{
+		Query: graphql.NewObject(graphql.ObjectConfig{
+			Name: "Query",
+			Fields: graphql.Fields{
+				"a": &graphql.Field{
+					Type: graphql.String,
+					AppliedDirectives: []*graphql.AppliedDirective{
+						{Name: "meta", Args: map[string]any{
+							"keys":  map[appliedDirectiveKey]any{"b": 2, "a": "x"},
+							"codes": map[int]any{10: true},
+							"mixed": map[any]any{"c": []any{1.5}, 3: true},
+						}},
+					},
+				},
+			},
+		}),
+	})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	printed := graphql.PrintSchema(&schema)
+	expected := `a: String @meta(codes: {}, keys: {a: "x", b: 2}, mixed: {c: [1.5]})`
+	if !strings.Contains(printed, expected) {
+		t.Fatalf("expected the schema to contain %q, got: %v", expected, printed)
+	}
+}
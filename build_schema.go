@@ -0,0 +1,722 @@
+package graphql
+
+import (
+	"fmt"
+	"strconv"
+
+	"github.com/dagger/graphql/language/ast"
+	"github.com/dagger/graphql/language/parser"
+)
+
+// BuildSchemaOptions Options for BuildSchema() and BuildASTSchema()
+type BuildSchemaOptions struct {
+	// Resolvers maps type names to field names to the resolve functions of
+	// their fields. Fields without one use DefaultResolveFn.
+	Resolvers map[string]map[string]FieldResolveFn
+
+	// Subscribers maps type names to field names to the subscribe functions
+	// of their fields.
+	Subscribers map[string]map[string]FieldResolveFn
+
+	// Scalars maps the names of the scalars declared in the SDL to their
+	// implementation. Scalars without one pass values through as they are.
+	Scalars map[string]*Scalar
+
+	// EnumValues maps enum names to enum value names to their internal value.
+	// Enum values without one are represented by their name.
+	EnumValues map[string]map[string]any
+
+	// TypeResolvers maps interface and union names to the functions resolving
+	// their concrete type. Abstract types without one resolve the type whose
+	// IsTypeOf function matches, or else the type named by the "__typename"
+	// key of map values.
+	TypeResolvers map[string]ResolveTypeFn
+
+	// IsTypeOf maps object type names to their IsTypeOf function.
+	IsTypeOf map[string]IsTypeOfFn
+
+	// Extensions are added to the built schema.
+	Extensions []Extension
+
+	// FieldMiddleware wraps the resolvers of the built schema, see
+	// SchemaConfig.FieldMiddleware.
+	FieldMiddleware []func(next FieldResolveFn) FieldResolveFn
+
+	// LenientSerialization is SchemaConfig.LenientSerialization for the built
+	// schema.
+	LenientSerialization bool
+}
+
+// BuildSchema builds a Schema from the type definitions of the given SDL
+// document.
+//
+// Example:
+//
+//	schema, err := graphql.BuildSchema(`
+//	  type Query {
+//	    hello(name: String = "world"): String
+//	  }
+//	`, graphql.BuildSchemaOptions{
+//	  Resolvers: map[string]map[string]graphql.FieldResolveFn{
+//	    "Query": {
+//	      "hello": func(p graphql.ResolveParams) (any, error) {
+//	        return "hello " + p.Args["name"].(string), nil
+//	      },
+//	    },
+//	  },
+//	})
+func BuildSchema(sdl string, opts BuildSchemaOptions) (Schema, error) {
+	doc, err := parser.Parse(parser.ParseParams{Source: sdl})
+	if err != nil {
+		return Schema{}, err
+	}
+	return BuildASTSchema(doc, opts)
+}
+
+// BuildASTSchema builds a Schema from the type definitions of a parsed SDL
+// document, see BuildSchema.
+func BuildASTSchema(doc *ast.Document, opts BuildSchemaOptions) (Schema, error) {
+	b := &schemaBuilder{
+		opts:        opts,
+		definitions: map[string]ast.Node{},
+		extensions:  map[string][]*ast.ObjectDefinition{},
+		types:       map[string]Type{},
+	}
+	if err := b.collect(doc); err != nil {
+		return Schema{}, err
+	}
+	if err := b.checkTypeReferences(); err != nil {
+		return Schema{}, err
+	}
+
+	config := SchemaConfig{
+		Extensions:           opts.Extensions,
+		FieldMiddleware:      opts.FieldMiddleware,
+		LenientSerialization: opts.LenientSerialization,
+	}
+	for _, name := range b.order {
+		config.Types = append(config.Types, b.namedType(name))
+	}
+
+	operationTypes := map[string]string{
+		ast.OperationTypeQuery:        "Query",
+		ast.OperationTypeMutation:     "Mutation",
+		ast.OperationTypeSubscription: "Subscription",
+	}
+	if b.schemaDef != nil {
+		operationTypes = map[string]string{}
+		for _, operationType := range b.schemaDef.OperationTypes {
+			operationTypes[operationType.Operation] = operationType.Type.Name.Value
+		}
+	}
+	var err error
+	if config.Query, err = b.operationType(operationTypes[ast.OperationTypeQuery]); err != nil {
+		return Schema{}, err
+	}
+	if config.Query == nil {
+		return Schema{}, fmt.Errorf("Must provide schema definition with query type or a type named Query.")
+	}
+	if config.Mutation, err = b.operationType(operationTypes[ast.OperationTypeMutation]); err != nil {
+		return Schema{}, err
+	}
+	if config.Subscription, err = b.operationType(operationTypes[ast.OperationTypeSubscription]); err != nil {
+		return Schema{}, err
+	}
+
+	config.Directives = append(config.Directives, SpecifiedDirectives...)
+	for _, def := range b.directives {
+		if isSpecifiedDirectiveName(def.Name.Value) {
+			continue
+		}
+		config.Directives = append(config.Directives, b.buildDirective(def))
+	}
+
+	schema, err := NewSchema(config)
+	if err != nil {
+		return schema, err
+	}
+	// errors found while resolving the thunks of the types
+	if b.err != nil {
+		return Schema{}, b.err
+	}
+	return schema, nil
+}
+
+// schemaBuilder builds the types of an SDL document. Types are built on first
+// use, and refer to each other through thunks, so that they may be recursive.
+type schemaBuilder struct {
+	opts BuildSchemaOptions
+
+	schemaDef   *ast.SchemaDefinition
+	definitions map[string]ast.Node
+	extensions  map[string][]*ast.ObjectDefinition
+	directives  []*ast.DirectiveDefinition
+	order       []string
+
+	types map[string]Type
+	err   error
+}
+
+func (b *schemaBuilder) collect(doc *ast.Document) error {
+	for _, def := range doc.Definitions {
+		switch def := def.(type) {
+		case *ast.SchemaDefinition:
+			if b.schemaDef != nil {
+				return fmt.Errorf("Must provide only one schema definition.")
+			}
+			b.schemaDef = def
+		case *ast.DirectiveDefinition:
+			b.directives = append(b.directives, def)
+		case *ast.TypeExtensionDefinition:
+			if def.Definition == nil {
+				continue
+			}
+			name := def.Definition.Name.Value
+			b.extensions[name] = append(b.extensions[name], def.Definition)
+		case *ast.ScalarDefinition, *ast.ObjectDefinition, *ast.InterfaceDefinition,
+			*ast.UnionDefinition, *ast.EnumDefinition, *ast.InputObjectDefinition:
+			name := typeDefinitionName(def)
+			if _, ok := b.definitions[name]; ok {
+				return fmt.Errorf(`Type "%v" was defined more than once.`, name)
+			}
+			b.definitions[name] = def
+			// specified scalars are provided by the schema
+			if _, ok := specifiedScalars[name]; !ok {
+				b.order = append(b.order, name)
+			}
+		default:
+			return fmt.Errorf("Unexpected %v in a schema definition document.", def.GetKind())
+		}
+	}
+	for name := range b.extensions {
+		if _, ok := b.definitions[name].(*ast.ObjectDefinition); !ok {
+			return fmt.Errorf(`Cannot extend type "%v" because it is not defined as an object type.`, name)
+		}
+	}
+	return nil
+}
+
+func typeDefinitionName(def ast.Node) string {
+	switch def := def.(type) {
+	case *ast.ScalarDefinition:
+		return def.Name.Value
+	case *ast.ObjectDefinition:
+		return def.Name.Value
+	case *ast.InterfaceDefinition:
+		return def.Name.Value
+	case *ast.UnionDefinition:
+		return def.Name.Value
+	case *ast.EnumDefinition:
+		return def.Name.Value
+	case *ast.InputObjectDefinition:
+		return def.Name.Value
+	}
+	return ""
+}
+
+var specifiedScalars = map[string]*Scalar{
+	"Int":     Int,
+	"Float":   Float,
+	"String":  String,
+	"Boolean": Boolean,
+	"ID":      ID,
+}
+
+func isSpecifiedDirectiveName(name string) bool {
+	for _, directive := range SpecifiedDirectives {
+		if directive.Name == name {
+			return true
+		}
+	}
+	return false
+}
+
+// checkTypeReferences ensures that every type referenced by the document is
+// defined, so that building types from thunks cannot fail on unknown types.
+func (b *schemaBuilder) checkTypeReferences() error {
+	check := func(ttype ast.Type, input bool) error {
+		named := namedTypeAST(ttype)
+		if named == nil || named.Name == nil {
+			return nil
+		}
+		name := named.Name.Value
+		if _, ok := specifiedScalars[name]; ok {
+			return nil
+		}
+		switch b.definitions[name].(type) {
+		case nil:
+			return fmt.Errorf(`Unknown type "%v".`, name)
+		case *ast.ScalarDefinition, *ast.EnumDefinition:
+		case *ast.InputObjectDefinition:
+			if !input {
+				return fmt.Errorf(`Input type "%v" cannot be used as an output type.`, name)
+			}
+		default:
+			if input {
+				return fmt.Errorf(`Output type "%v" cannot be used as an input type.`, name)
+			}
+		}
+		return nil
+	}
+	checkFields := func(fields []*ast.FieldDefinition) error {
+		for _, field := range fields {
+			if err := check(field.Type, false); err != nil {
+				return err
+			}
+			for _, arg := range field.Arguments {
+				if err := check(arg.Type, true); err != nil {
+					return err
+				}
+			}
+		}
+		return nil
+	}
+	checkInterfaces := func(name string, interfaces []*ast.Named) error {
+		for _, iface := range interfaces {
+			if _, ok := b.definitions[iface.Name.Value].(*ast.InterfaceDefinition); !ok {
+				return fmt.Errorf(`Type "%v" must only implement Interface types, it cannot implement %v.`, name, iface.Name.Value)
+			}
+		}
+		return nil
+	}
+	checkObject := func(def *ast.ObjectDefinition) error {
+		if err := checkInterfaces(def.Name.Value, def.Interfaces); err != nil {
+			return err
+		}
+		return checkFields(def.Fields)
+	}
+
+	for _, name := range b.order {
+		var err error
+		switch def := b.definitions[name].(type) {
+		case *ast.ObjectDefinition:
+			err = checkObject(def)
+			for _, ext := range b.extensions[name] {
+				if err == nil {
+					err = checkObject(ext)
+				}
+			}
+		case *ast.InterfaceDefinition:
+			err = checkInterfaces(name, def.Interfaces)
+			if err == nil {
+				err = checkFields(def.Fields)
+			}
+		case *ast.UnionDefinition:
+			for _, member := range def.Types {
+				if _, ok := b.definitions[member.Name.Value].(*ast.ObjectDefinition); !ok {
+					err = fmt.Errorf(`Union type "%v" can only include Object types, it cannot include %v.`, name, member.Name.Value)
+					break
+				}
+			}
+		case *ast.InputObjectDefinition:
+			for _, field := range def.Fields {
+				if err = check(field.Type, true); err != nil {
+					break
+				}
+			}
+		}
+		if err != nil {
+			return err
+		}
+	}
+	for _, def := range b.directives {
+		for _, arg := range def.Arguments {
+			if err := check(arg.Type, true); err != nil {
+				return err
+			}
+		}
+	}
+	return nil
+}
+
+func namedTypeAST(ttype ast.Type) *ast.Named {
+	switch ttype := ttype.(type) {
+	case *ast.Named:
+		return ttype
+	case *ast.List:
+		return namedTypeAST(ttype.Type)
+	case *ast.NonNull:
+		return namedTypeAST(ttype.Type)
+	}
+	return nil
+}
+
+func (b *schemaBuilder) operationType(name string) (*Object, error) {
+	if name == "" {
+		return nil, nil
+	}
+	def, ok := b.definitions[name]
+	if !ok {
+		if b.schemaDef != nil {
+			return nil, fmt.Errorf(`Specified operation type "%v" not found in document.`, name)
+		}
+		return nil, nil
+	}
+	if _, ok := def.(*ast.ObjectDefinition); !ok {
+		return nil, fmt.Errorf(`Operation type "%v" must be an Object type.`, name)
+	}
+	return b.namedType(name).(*Object), nil
+}
+
+// namedType returns the type of the given name, building it on first use.
+func (b *schemaBuilder) namedType(name string) Type {
+	if ttype, ok := b.types[name]; ok {
+		return ttype
+	}
+	if scalar, ok := specifiedScalars[name]; ok {
+		return scalar
+	}
+
+	var ttype Type
+	switch def := b.definitions[name].(type) {
+	case *ast.ScalarDefinition:
+		ttype = b.buildScalar(def)
+	case *ast.ObjectDefinition:
+		ttype = b.buildObject(def)
+	case *ast.InterfaceDefinition:
+		ttype = b.buildInterface(def)
+	case *ast.UnionDefinition:
+		ttype = b.buildUnion(def)
+	case *ast.EnumDefinition:
+		ttype = b.buildEnum(def)
+	case *ast.InputObjectDefinition:
+		ttype = b.buildInputObject(def)
+	}
+	b.types[name] = ttype
+	return ttype
+}
+
+func (b *schemaBuilder) typeFromAST(ttype ast.Type) Type {
+	switch ttype := ttype.(type) {
+	case *ast.List:
+		return NewList(b.typeFromAST(ttype.Type))
+	case *ast.NonNull:
+		return NewNonNull(b.typeFromAST(ttype.Type))
+	case *ast.Named:
+		return b.namedType(ttype.Name.Value)
+	}
+	return nil
+}
+
+func (b *schemaBuilder) setErr(err error) {
+	if b.err == nil {
+		b.err = err
+	}
+}
+
+func (b *schemaBuilder) buildScalar(def *ast.ScalarDefinition) *Scalar {
+	if scalar, ok := b.opts.Scalars[def.Name.Value]; ok {
+		return scalar
+	}
+	identity := func(value any) (any, error) {
+		return value, nil
+	}
+	return NewScalar(ScalarConfig{
+		Name:        def.Name.Value,
+		Description: descriptionValue(def.Description),
+		Serialize:   identity,
+		ParseValue:  identity,
+		ParseLiteral: func(valueAST ast.Value) (any, error) {
+			return literalValue(valueAST), nil
+		},
+	})
+}
+
+func (b *schemaBuilder) buildObject(def *ast.ObjectDefinition) *Object {
+	name := def.Name.Value
+	defs := append([]*ast.ObjectDefinition{def}, b.extensions[name]...)
+	directives := []*ast.Directive{}
+	for _, def := range defs {
+		directives = append(directives, def.Directives...)
+	}
+	return NewObject(ObjectConfig{
+		Name:              name,
+		Description:       descriptionValue(def.Description),
+		IsTypeOf:          b.opts.IsTypeOf[name],
+		AppliedDirectives: b.appliedDirectives(directives),
+		Interfaces: InterfacesThunk(func() []*Interface {
+			interfaces := []*Interface{}
+			for _, def := range defs {
+				for _, iface := range def.Interfaces {
+					interfaces = append(interfaces, b.namedType(iface.Name.Value).(*Interface))
+				}
+			}
+			return interfaces
+		}),
+		Fields: FieldsThunk(func() Fields {
+			fields := Fields{}
+			for _, def := range defs {
+				for _, field := range def.Fields {
+					fields[field.Name.Value] = b.buildField(name, field)
+				}
+			}
+			return fields
+		}),
+	})
+}
+
+func (b *schemaBuilder) buildInterface(def *ast.InterfaceDefinition) *Interface {
+	name := def.Name.Value
+	return NewInterface(InterfaceConfig{
+		Name:        name,
+		Description: descriptionValue(def.Description),
+		ResolveType: b.resolveTypeFn(name),
+		Interfaces: InterfacesThunk(func() []*Interface {
+			interfaces := []*Interface{}
+			for _, iface := range def.Interfaces {
+				interfaces = append(interfaces, b.namedType(iface.Name.Value).(*Interface))
+			}
+			return interfaces
+		}),
+		Fields: FieldsThunk(func() Fields {
+			fields := Fields{}
+			for _, field := range def.Fields {
+				fields[field.Name.Value] = b.buildField(name, field)
+			}
+			return fields
+		}),
+	})
+}
+
+func (b *schemaBuilder) buildUnion(def *ast.UnionDefinition) *Union {
+	name := def.Name.Value
+	return NewUnion(UnionConfig{
+		Name:        name,
+		Description: descriptionValue(def.Description),
+		ResolveType: b.resolveTypeFn(name),
+		Types: UnionTypesThunk(func() []*Object {
+			types := []*Object{}
+			for _, member := range def.Types {
+				types = append(types, b.namedType(member.Name.Value).(*Object))
+			}
+			return types
+		}),
+	})
+}
+
+// resolveTypeFn returns the type resolver of an abstract type, which defaults
+// to matching IsTypeOf functions, and then to the "__typename" of map values.
+func (b *schemaBuilder) resolveTypeFn(name string) ResolveTypeFn {
+	if resolveType, ok := b.opts.TypeResolvers[name]; ok {
+		return resolveType
+	}
+	return func(p ResolveTypeParams) *Object {
+		abstractType, _ := b.types[name].(Abstract)
+		if abstractType != nil {
+			if ttype := defaultResolveTypeFn(p, abstractType); ttype != nil {
+				return ttype
+			}
+		}
+		value, ok := p.Value.(map[string]any)
+		if !ok {
+			return nil
+		}
+		typeName, _ := value["__typename"].(string)
+		ttype, _ := p.Info.Schema.Type(typeName).(*Object)
+		return ttype
+	}
+}
+
+func (b *schemaBuilder) buildEnum(def *ast.EnumDefinition) *Enum {
+	name := def.Name.Value
+	values := EnumValueConfigMap{}
+	for _, value := range def.Values {
+		var internal any = value.Name.Value
+		if v, ok := b.opts.EnumValues[name][value.Name.Value]; ok {
+			internal = v
+		}
+		values[value.Name.Value] = &EnumValueConfig{
+			Value:             internal,
+			Description:       descriptionValue(value.Description),
+			DeprecationReason: deprecationReason(value.Directives),
+			AppliedDirectives: b.appliedDirectives(value.Directives),
+		}
+	}
+	return NewEnum(EnumConfig{
+		Name:        name,
+		Description: descriptionValue(def.Description),
+		Values:      values,
+	})
+}
+
+func (b *schemaBuilder) buildInputObject(def *ast.InputObjectDefinition) *InputObject {
+	return NewInputObject(InputObjectConfig{
+		Name:        def.Name.Value,
+		Description: descriptionValue(def.Description),
+		IsOneOf:     hasDirective(def.Directives, OneOfDirective.Name),
+		Fields: InputObjectConfigFieldMapThunk(func() InputObjectConfigFieldMap {
+			fields := InputObjectConfigFieldMap{}
+			for _, field := range def.Fields {
+				ttype := b.typeFromAST(field.Type).(Input)
+				fields[field.Name.Value] = &InputObjectFieldConfig{
+					Type:              ttype,
+					Description:       descriptionValue(field.Description),
+					DefaultValue:      b.defaultValue(field, ttype),
+					AppliedDirectives: b.appliedDirectives(field.Directives),
+				}
+			}
+			return fields
+		}),
+	})
+}
+
+func (b *schemaBuilder) buildField(typeName string, def *ast.FieldDefinition) *Field {
+	return &Field{
+		Name:              def.Name.Value,
+		Type:              b.typeFromAST(def.Type).(Output),
+		Description:       descriptionValue(def.Description),
+		DeprecationReason: deprecationReason(def.Directives),
+		AppliedDirectives: b.appliedDirectives(def.Directives),
+		Args:              b.buildArgs(def.Arguments),
+		Resolve:           b.opts.Resolvers[typeName][def.Name.Value],
+		Subscribe:         b.opts.Subscribers[typeName][def.Name.Value],
+	}
+}
+
+func (b *schemaBuilder) buildArgs(defs []*ast.InputValueDefinition) FieldConfigArgument {
+	args := FieldConfigArgument{}
+	for _, def := range defs {
+		ttype := b.typeFromAST(def.Type).(Input)
+		args = append(args, &ArgumentConfig{
+			Name:              def.Name.Value,
+			Type:              ttype,
+			Description:       descriptionValue(def.Description),
+			DefaultValue:      b.defaultValue(def, ttype),
+			AppliedDirectives: b.appliedDirectives(def.Directives),
+		})
+	}
+	return args
+}
+
+func (b *schemaBuilder) buildDirective(def *ast.DirectiveDefinition) *Directive {
+	locations := []string{}
+	for _, location := range def.Locations {
+		locations = append(locations, location.Value)
+	}
+	return NewDirective(DirectiveConfig{
+		Name:         def.Name.Value,
+		Description:  descriptionValue(def.Description),
+		Locations:    locations,
+		Args:         b.buildArgs(def.Arguments),
+		IsRepeatable: def.Repeatable,
+	})
+}
+
+// appliedDirectives returns the directives applied in the SDL, but for the
+// specified ones, which are already reflected in the built schema. Arguments
+// are coerced according to the definition of the directive, if any.
+func (b *schemaBuilder) appliedDirectives(directives []*ast.Directive) []*AppliedDirective {
+	var applied []*AppliedDirective
+	for _, directive := range directives {
+		if directive.Name == nil || isSpecifiedDirectiveName(directive.Name.Value) {
+			continue
+		}
+		var definition *ast.DirectiveDefinition
+		for _, def := range b.directives {
+			if def.Name.Value == directive.Name.Value {
+				definition = def
+			}
+		}
+		args := map[string]any{}
+		for _, arg := range directive.Arguments {
+			var value any
+			if definition != nil {
+				for _, argDef := range definition.Arguments {
+					if argDef.Name.Value == arg.Name.Value {
+						value, _ = valueFromAST(arg.Value, b.typeFromAST(argDef.Type).(Input), nil)
+					}
+				}
+			}
+			if value == nil {
+				value = literalValue(arg.Value)
+			}
+			args[arg.Name.Value] = value
+		}
+		applied = append(applied, &AppliedDirective{
+			Name: directive.Name.Value,
+			Args: args,
+		})
+	}
+	return applied
+}
+
+func (b *schemaBuilder) defaultValue(def *ast.InputValueDefinition, ttype Input) any {
+	if def.DefaultValue == nil {
+		return nil
+	}
+	value, err := valueFromAST(def.DefaultValue, ttype, nil)
+	if err == nil && value == nil {
+		err = fmt.Errorf("expected type %v", ttype)
+	}
+	if err != nil {
+		b.setErr(fmt.Errorf(`Invalid default value for "%v": %v`, def.Name.Value, err))
+	}
+	return value
+}
+
+func descriptionValue(description *ast.StringValue) string {
+	if description == nil {
+		return ""
+	}
+	return description.Value
+}
+
+// deprecationReason returns the reason of the @deprecated directive among the
+// given ones, if any.
+func deprecationReason(directives []*ast.Directive) string {
+	for _, directive := range directives {
+		if directive.Name == nil || directive.Name.Value != DeprecatedDirective.Name {
+			continue
+		}
+		argValues := getArgumentValues(DeprecatedDirective.Args, directive.Arguments, nil)
+		if reason, ok := argValues["reason"].(string); ok {
+			return reason
+		}
+		return DefaultDeprecationReason
+	}
+	return ""
+}
+
+func hasDirective(directives []*ast.Directive, name string) bool {
+	for _, directive := range directives {
+		if directive.Name != nil && directive.Name.Value == name {
+			return true
+		}
+	}
+	return false
+}
+
+// literalValue returns the Go value of a literal, regardless of its type.
+func literalValue(valueAST ast.Value) any {
+	switch valueAST := valueAST.(type) {
+	case *ast.IntValue:
+		if value, err := strconv.ParseInt(valueAST.Value, 10, 64); err == nil {
+			return int(value)
+		}
+		return nil
+	case *ast.FloatValue:
+		if value, err := strconv.ParseFloat(valueAST.Value, 64); err == nil {
+			return value
+		}
+		return nil
+	case *ast.StringValue:
+		return valueAST.Value
+	case *ast.BooleanValue:
+		return valueAST.Value
+	case *ast.EnumValue:
+		return valueAST.Value
+	case *ast.ListValue:
+		values := []any{}
+		for _, value := range valueAST.Values {
+			values = append(values, literalValue(value))
+		}
+		return values
+	case *ast.ObjectValue:
+		values := map[string]any{}
+		for _, field := range valueAST.Fields {
+			values[field.Name.Value] = literalValue(field.Value)
+		}
+		return values
+	}
+	return nil
+}
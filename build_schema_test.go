@@ -0,0 +1,213 @@
+package graphql_test
+
+import (
+	"reflect"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/dagger/graphql"
+	"github.com/dagger/graphql/language/ast"
+	"github.com/dagger/graphql/testutil"
+)
+
+const buildSchemaSDL = `
+schema {
+  query: Root
+  mutation: Mutations
+}
+
+scalar Date
+
+"""
+One of the films in the Star Wars Trilogy.
+"""
+enum Episode {
+  NEWHOPE
+  EMPIRE
+  JEDI @deprecated(reason: "Not released yet")
+}
+
+interface Character {
+  name: String!
+}
+
+type Human implements Character {
+  name: String!
+  born: Date
+}
+
+type Droid implements Character {
+  name: String!
+  function: String @deprecated
+}
+
+union SearchResult = Human | Droid
+
+input ReviewInput {
+  stars: Int!
+  commentary: String = "no comment"
+}
+
+type Review {
+  episode: Episode
+  stars: Int!
+  commentary: String
+}
+
+type Root {
+  hero(episode: Episode = NEWHOPE): Character
+  search(text: String!): [SearchResult!]!
+}
+
+extend type Root {
+  today: Date
+}
+
+type Mutations {
+  createReview(episode: Episode!, review: ReviewInput!): Review
+}
+`
+
+var buildSchemaCharacters = []any{
+	map[string]any{"__typename": "Human", "name": "Luke", "born": time.Date(1977, 5, 25, 0, 0, 0, 0, time.UTC)},
+	map[string]any{"__typename": "Droid", "name": "R2-D2", "function": "Astromech"},
+}
+
+func buildTestSchema(t *testing.T) graphql.Schema {
+	dateScalar := graphql.NewScalar(graphql.ScalarConfig{
+		Name: "Date",
+		Serialize: func(value any) (any, error) {
+			return value.(time.Time).Format("2006-01-02"), nil
+		},
+		ParseValue: func(value any) (any, error) {
+			return time.Parse("2006-01-02", value.(string))
+		},
+		ParseLiteral: func(valueAST ast.Value) (any, error) {
+			return time.Parse("2006-01-02", valueAST.GetValue().(string))
+		},
+	})
+	schema, err := graphql.BuildSchema(buildSchemaSDL, graphql.BuildSchemaOptions{
+		Scalars: map[string]*graphql.Scalar{"Date": dateScalar},
+		Resolvers: map[string]map[string]graphql.FieldResolveFn{
+			"Root": {
+				"hero": func(p graphql.ResolveParams) (any, error) {
+					if p.Args["episode"] == "EMPIRE" {
+						return buildSchemaCharacters[0], nil
+					}
+					return buildSchemaCharacters[1], nil
+				},
+				"search": func(p graphql.ResolveParams) (any, error) {
+					return buildSchemaCharacters, nil
+				},
+				"today": func(p graphql.ResolveParams) (any, error) {
+					return time.Date(2020, 1, 2, 0, 0, 0, 0, time.UTC), nil
+				},
+			},
+			"Mutations": {
+				"createReview": func(p graphql.ResolveParams) (any, error) {
+					review := p.Args["review"].(map[string]any)
+					return map[string]any{
+						"episode":    p.Args["episode"],
+						"stars":      review["stars"],
+						"commentary": review["commentary"],
+					}, nil
+				},
+			},
+		},
+	})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	return schema
+}
+
+func TestBuildSchema_ExecutesQueries(t *testing.T) {
+	schema := buildTestSchema(t)
+	result := graphql.Do(graphql.Params{
+		Schema: schema,
+		RequestString: `{
+			hero { name }
+			empireHero: hero(episode: EMPIRE) { name ... on Human { born } }
+			search(text: "any") {
+				__typename
+				... on Human { name }
+				... on Droid { function }
+			}
+			today
+		}`,
+	})
+	expected := &graphql.Result{
+		Data: map[string]any{
+			"hero":       map[string]any{"name": "R2-D2"},
+			"empireHero": map[string]any{"name": "Luke", "born": "1977-05-25"},
+			"search": []any{
+				map[string]any{"__typename": "Human", "name": "Luke"},
+				map[string]any{"__typename": "Droid", "function": "Astromech"},
+			},
+			"today": "2020-01-02",
+		},
+	}
+	if !reflect.DeepEqual(expected, result) {
+		t.Fatalf("Unexpected result, Diff: %v", testutil.Diff(expected, result))
+	}
+}
+
+func TestBuildSchema_ExecutesMutations(t *testing.T) {
+	schema := buildTestSchema(t)
+	result := graphql.Do(graphql.Params{
+		Schema:        schema,
+		RequestString: `mutation { createReview(episode: JEDI, review: {stars: 5}) { episode stars commentary } }`,
+	})
+	expected := &graphql.Result{
+		Data: map[string]any{
+			"createReview": map[string]any{
+				"episode":    "JEDI",
+				"stars":      int64(5),
+				"commentary": "no comment",
+			},
+		},
+	}
+	if !reflect.DeepEqual(expected, result) {
+		t.Fatalf("Unexpected result, Diff: %v", testutil.Diff(expected, result))
+	}
+}
+
+func TestBuildSchema_BuildsDescriptionsAndDeprecations(t *testing.T) {
+	schema := buildTestSchema(t)
+	if schema.QueryType().Name() != "Root" || schema.MutationType().Name() != "Mutations" {
+		t.Fatalf("wrong operation types: %v, %v", schema.QueryType(), schema.MutationType())
+	}
+	if description := schema.Type("Episode").Description(); description != "One of the films in the Star Wars Trilogy." {
+		t.Fatalf("wrong description: %q", description)
+	}
+	droid := schema.Type("Droid").(*graphql.Object)
+	if reason := droid.Fields()["function"].DeprecationReason; reason != graphql.DefaultDeprecationReason {
+		t.Fatalf("wrong deprecation reason: %q", reason)
+	}
+	for _, value := range schema.Type("Episode").(*graphql.Enum).Values() {
+		if value.Name == "JEDI" && value.DeprecationReason != "Not released yet" {
+			t.Fatalf("wrong deprecation reason: %q", value.DeprecationReason)
+		}
+	}
+}
+
+func TestBuildSchema_RejectsInvalidDocuments(t *testing.T) {
+	tests := []struct {
+		sdl      string
+		expected string
+	}{
+		{`type Query { a: Unknown }`, `Unknown type "Unknown".`},
+		{`type Query { a: String } type Query { b: String }`, `Type "Query" was defined more than once.`},
+		{`type Foo { a: String }`, `Must provide schema definition with query type or a type named Query.`},
+		{`input In { a: String } type Query { a: In }`, `Input type "In" cannot be used as an output type.`},
+		{`type Query { a(arg: Query): String }`, `Output type "Query" cannot be used as an input type.`},
+		{`type Query { a: String } query { a }`, `Unexpected OperationDefinition in a schema definition document.`},
+	}
+	for _, test := range tests {
+		_, err := graphql.BuildSchema(test.sdl, graphql.BuildSchemaOptions{})
+		if err == nil || !strings.Contains(err.Error(), test.expected) {
+			t.Fatalf("expected error %q for %q, got %v", test.expected, test.sdl, err)
+		}
+	}
+}
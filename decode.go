@@ -0,0 +1,198 @@
+package graphql
+
+import (
+	"encoding"
+	"fmt"
+	"math"
+	"reflect"
+	"strings"
+)
+
+// ArgumentDecodeError is returned by DecodeArgs when a value cannot be
+// decoded.
+type ArgumentDecodeError struct {
+	// Path is the path of the value within the arguments, made of the names
+	// of arguments and input object fields and of list indices.
+	Path []any
+	Err  error
+}
+
+func (e *ArgumentDecodeError) Error() string {
+	var path strings.Builder
+	for i, key := range e.Path {
+		switch key := key.(type) {
+		case int:
+			fmt.Fprintf(&path, "[%d]", key)
+		default:
+			if i > 0 {
+				path.WriteString(".")
+			}
+			fmt.Fprintf(&path, "%v", key)
+		}
+	}
+	return fmt.Sprintf("Cannot decode argument %v: %v", path.String(), e.Err)
+}
+
+func (e *ArgumentDecodeError) Unwrap() error {
+	return e.Err
+}
+
+// DecodeArgs decodes the arguments of a field, as given to its resolver in
+// ResolveParams.Args, into dst, a pointer to a struct or to a map[string]any.
+// It is the counterpart of BindArg.
+//
+// Arguments and the fields of input objects are decoded into the exported
+// struct fields named after them, following the tag rules of
+// DefaultResolveFn, lists into slices, and null values into nil pointers.
+// Strings are decoded into types implementing encoding.TextUnmarshaler, and
+// other values, e.g. the values of enums, are converted into the type they
+// are decoded into when possible.
+//
+// A value which cannot be decoded is reported as an *ArgumentDecodeError.
+//
+//	func(p graphql.ResolveParams) (any, error) {
+//		var args struct {
+//			Filter struct {
+//				Tags  []string
+//				Since *time.Time
+//			}
+//		}
+//		if err := graphql.DecodeArgs(p.Args, &args); err != nil {
+//			return nil, err
+//		}
+//		...
+//	}
+func DecodeArgs(args map[string]any, dst any) error {
+	v := reflect.ValueOf(dst)
+	if v.Kind() != reflect.Ptr || v.IsNil() {
+		return fmt.Errorf("DecodeArgs expects a non-nil pointer but got: %T", dst)
+	}
+	if !isArgsStruct(v.Type().Elem()) && v.Type().Elem() != reflect.TypeOf(map[string]any{}) {
+		return fmt.Errorf("DecodeArgs expects a pointer to a struct or map[string]any but got: %T", dst)
+	}
+	return decodeArgs(args, v.Elem())
+}
+
+// decodeArgs decodes the given arguments into dst, a struct, a pointer to a
+// struct or a map[string]any.
+func decodeArgs(args map[string]any, dst reflect.Value) error {
+	if dst.Kind() == reflect.Map {
+		dst.Set(reflect.ValueOf(args))
+		return nil
+	}
+	return decodeValue(args, dst, nil)
+}
+
+// decodeValue decodes a coerced input value found at the given path into dst
+func decodeValue(value any, dst reflect.Value, path []any) error {
+	if value == nil {
+		dst.Set(reflect.Zero(dst.Type()))
+		return nil
+	}
+	src := reflect.ValueOf(value)
+	if src.Type().AssignableTo(dst.Type()) {
+		dst.Set(src)
+		return nil
+	}
+	if text, ok := value.(string); ok && dst.Kind() != reflect.Ptr &&
+		reflect.PtrTo(dst.Type()).Implements(textUnmarshalerType) {
+		ptr := reflect.New(dst.Type())
+		if err := ptr.Interface().(encoding.TextUnmarshaler).UnmarshalText([]byte(text)); err != nil {
+			return decodeError(path, err)
+		}
+		dst.Set(ptr.Elem())
+		return nil
+	}
+
+	switch dst.Kind() {
+	case reflect.Ptr:
+		elem := reflect.New(dst.Type().Elem())
+		if err := decodeValue(value, elem.Elem(), path); err != nil {
+			return err
+		}
+		dst.Set(elem)
+		return nil
+	case reflect.Struct:
+		fields, ok := value.(map[string]any)
+		if !ok {
+			break
+		}
+		for i := 0; i < dst.NumField(); i++ {
+			name, ok := argumentName(dst.Type().Field(i))
+			if !ok {
+				continue
+			}
+			if err := decodeValue(fields[name], dst.Field(i), append(path, name)); err != nil {
+				return err
+			}
+		}
+		return nil
+	case reflect.Slice:
+		if src.Kind() != reflect.Slice {
+			break
+		}
+		items := reflect.MakeSlice(dst.Type(), src.Len(), src.Len())
+		for i := 0; i < src.Len(); i++ {
+			if err := decodeValue(src.Index(i).Interface(), items.Index(i), append(path, i)); err != nil {
+				return err
+			}
+		}
+		dst.Set(items)
+		return nil
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+		n, ok := integerOf(src)
+		if !ok {
+			break
+		}
+		if dst.OverflowInt(n) {
+			return decodeError(path, fmt.Errorf("value %v overflows %v", value, dst.Type()))
+		}
+		dst.SetInt(n)
+		return nil
+	case reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64:
+		n, ok := integerOf(src)
+		if !ok {
+			break
+		}
+		if n < 0 || dst.OverflowUint(uint64(n)) {
+			return decodeError(path, fmt.Errorf("value %v overflows %v", value, dst.Type()))
+		}
+		dst.SetUint(uint64(n))
+		return nil
+	case reflect.Float32, reflect.Float64:
+		if _, ok := integerOf(src); !ok && src.Kind() != reflect.Float32 && src.Kind() != reflect.Float64 {
+			break
+		}
+		dst.Set(src.Convert(dst.Type()))
+		return nil
+	default:
+		// e.g. named string types for enum values
+		if src.Kind() == dst.Kind() && src.CanConvert(dst.Type()) {
+			dst.Set(src.Convert(dst.Type()))
+			return nil
+		}
+	}
+	return decodeError(path, fmt.Errorf("cannot decode %T into %v", value, dst.Type()))
+}
+
+func decodeError(path []any, err error) error {
+	return &ArgumentDecodeError{
+		Path: append([]any{}, path...),
+		Err:  err,
+	}
+}
+
+// integerOf returns the value of an integer, and false if it is not one or
+// overflows an int64
+func integerOf(v reflect.Value) (int64, bool) {
+	switch v.Kind() {
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+		return v.Int(), true
+	case reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64:
+		if v.Uint() > math.MaxInt64 {
+			return 0, false
+		}
+		return int64(v.Uint()), true
+	}
+	return 0, false
+}
@@ -0,0 +1,182 @@
+package graphql_test
+
+import (
+	"errors"
+	"reflect"
+	"testing"
+	"time"
+
+	"github.com/dagger/graphql"
+	"github.com/dagger/graphql/testutil"
+)
+
+type decodedFilter struct {
+	Roles    []Role     `json:"roles"`
+	Versions []*Version `json:"versions"`
+	Since    *time.Time `json:"since"`
+	Limit    uint8      `json:"limit"`
+}
+
+type decodedArgs struct {
+	Query   string         `json:"query"`
+	Filter  *decodedFilter `json:"filter"`
+	Page    *int           `json:"page"`
+	Weights []float64      `json:"weights"`
+}
+
+func TestDecodeArgs(t *testing.T) {
+	roleEnum := graphql.NewEnum(graphql.EnumConfig{
+		Name: "Role",
+		Values: graphql.EnumValueConfigMap{
+			"ADMIN": &graphql.EnumValueConfig{Value: "admin"},
+			"GUEST": &graphql.EnumValueConfig{Value: "guest"},
+		},
+	})
+	filterInput := graphql.NewInputObject(graphql.InputObjectConfig{
+		Name: "Filter",
+		Fields: graphql.InputObjectConfigFieldMap{
+			"roles":    &graphql.InputObjectFieldConfig{Type: graphql.NewList(roleEnum)},
+			"versions": &graphql.InputObjectFieldConfig{Type: graphql.NewList(graphql.String)},
+			"since":    &graphql.InputObjectFieldConfig{Type: graphql.DateTime},
+			"limit":    &graphql.InputObjectFieldConfig{Type: graphql.Int},
+		},
+	})
+	var decoded decodedArgs
+	schema, err := graphql.NewSchema(graphql.SchemaConfig{
+		Query: graphql.NewObject(graphql.ObjectConfig{
+			Name: "Query",
+			Fields: graphql.Fields{
+				"search": &graphql.Field{
+					Type: graphql.Boolean,
+					Args: graphql.FieldConfigArgument{
+						&graphql.ArgumentConfig{Name: "query", Type: graphql.String},
+						&graphql.ArgumentConfig{Name: "filter", Type: filterInput},
+						&graphql.ArgumentConfig{Name: "page", Type: graphql.Int},
+						&graphql.ArgumentConfig{Name: "weights", Type: graphql.NewList(graphql.Float)},
+					},
+					Resolve: func(p graphql.ResolveParams) (any, error) {
+						decoded = decodedArgs{}
+						if err := graphql.DecodeArgs(p.Args, &decoded); err != nil {
+							return nil, err
+						}
+						return true, nil
+					},
+				},
+			},
+		}),
+	})
+	if err != nil {
+		t.Fatalf("Error in schema %v", err.Error())
+	}
+
+	page := 2
+	since := time.Date(2020, 1, 2, 3, 4, 5, 0, time.UTC)
+	for name, testCase := range map[string]struct {
+		Query    string
+		Args     map[string]any
+		Expected decodedArgs
+	}{
+		"literals": {
+			Query: `{
+				search(query: "q", page: 2, weights: [1, 0.5], filter: {
+					roles: [ADMIN, GUEST], versions: ["v1.2", "v2.0"], since: "2020-01-02T03:04:05Z", limit: 10
+				})
+			}`,
+			Expected: decodedArgs{
+				Query: "q",
+				Filter: &decodedFilter{
+					Roles:    []Role{RoleAdmin, RoleGuest},
+					Versions: []*Version{{1, 2}, {2, 0}},
+					Since:    &since,
+					Limit:    10,
+				},
+				Page:    &page,
+				Weights: []float64{1, 0.5},
+			},
+		},
+		"variables": {
+			Query: `query ($filter: Filter, $weights: [Float]) { search(filter: $filter, weights: $weights) }`,
+			Args: map[string]any{
+				"filter":  map[string]any{"roles": []any{"GUEST"}, "versions": []any{"v2.0", nil}},
+				"weights": []any{2},
+			},
+			Expected: decodedArgs{
+				Filter: &decodedFilter{
+					Roles:    []Role{RoleGuest},
+					Versions: []*Version{{2, 0}, nil},
+				},
+				Weights: []float64{2},
+			},
+		},
+		"omitted": {
+			Query:    `{ search }`,
+			Expected: decodedArgs{},
+		},
+	} {
+		t.Run(name, func(t *testing.T) {
+			result := testutil.TestExecute(t, graphql.ExecuteParams{
+				Schema: schema,
+				AST:    testutil.TestParse(t, testCase.Query),
+				Args:   testCase.Args,
+			})
+			if len(result.Errors) > 0 {
+				t.Fatalf("wrong result, unexpected errors: %v", result.Errors)
+			}
+			if !reflect.DeepEqual(testCase.Expected, decoded) {
+				t.Fatalf("Unexpected args, Diff: %v", testutil.Diff(testCase.Expected, decoded))
+			}
+		})
+	}
+}
+
+func TestDecodeArgs_Errors(t *testing.T) {
+	for name, testCase := range map[string]struct {
+		Args         map[string]any
+		Dst          any
+		Expected     string
+		ExpectedPath []any
+	}{
+		"text unmarshaler error": {
+			Args: map[string]any{
+				"filter": map[string]any{
+					"versions": []any{"v1.0", "latest"},
+				},
+			},
+			Dst:          &decodedArgs{},
+			Expected:     "Cannot decode argument filter.versions[1]: input does not match format",
+			ExpectedPath: []any{"filter", "versions", 1},
+		},
+		"overflow": {
+			Args: map[string]any{
+				"filter": map[string]any{"limit": 300},
+			},
+			Dst:          &decodedArgs{},
+			Expected:     "Cannot decode argument filter.limit: value 300 overflows uint8",
+			ExpectedPath: []any{"filter", "limit"},
+		},
+		"non-pointer": {
+			Args:     map[string]any{},
+			Dst:      decodedArgs{},
+			Expected: "DecodeArgs expects a non-nil pointer but got: graphql_test.decodedArgs",
+		},
+		"pointer to a non-struct": {
+			Args:     map[string]any{},
+			Dst:      new(string),
+			Expected: "DecodeArgs expects a pointer to a struct or map[string]any but got: *string",
+		},
+	} {
+		t.Run(name, func(t *testing.T) {
+			err := graphql.DecodeArgs(testCase.Args, testCase.Dst)
+			if err == nil || err.Error() != testCase.Expected {
+				t.Fatalf("expected error %q, got: %v", testCase.Expected, err)
+			}
+			var decodeErr *graphql.ArgumentDecodeError
+			if errors.As(err, &decodeErr) != (testCase.ExpectedPath != nil) {
+				t.Fatalf("expected an ArgumentDecodeError only with a path, got: %#v", err)
+			}
+			if decodeErr != nil && !reflect.DeepEqual(testCase.ExpectedPath, decodeErr.Path) {
+				t.Fatalf("Unexpected path, Diff: %v", testutil.Diff(testCase.ExpectedPath, decodeErr.Path))
+			}
+		})
+	}
+}
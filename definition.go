@@ -5,6 +5,7 @@ import (
 	"fmt"
 	"reflect"
 	"regexp"
+	"time"
 
 	"github.com/dagger/graphql/language/ast"
 )
@@ -367,11 +368,12 @@ type IsTypeOfFn func(p IsTypeOfParams) bool
 type InterfacesThunk func() []*Interface
 
 type ObjectConfig struct {
-	Name        string     `json:"name"`
-	Interfaces  any        `json:"interfaces"`
-	Fields      any        `json:"fields"`
-	IsTypeOf    IsTypeOfFn `json:"isTypeOf"`
-	Description string     `json:"description"`
+	Name              string              `json:"name"`
+	Interfaces        any                 `json:"interfaces"`
+	Fields            any                 `json:"fields"`
+	IsTypeOf          IsTypeOfFn          `json:"isTypeOf"`
+	Description       string              `json:"description"`
+	AppliedDirectives []*AppliedDirective `json:"appliedDirectives"`
 }
 
 type FieldsThunk func() Fields
@@ -417,7 +419,12 @@ func (gt *Object) Name() string {
 	return gt.PrivateName
 }
 func (gt *Object) Description() string {
-	return ""
+	return gt.PrivateDescription
+}
+
+// AppliedDirectives returns the directives applied to the object type.
+func (gt *Object) AppliedDirectives() []*AppliedDirective {
+	return gt.typeConfig.AppliedDirectives
 }
 func (gt *Object) String() string {
 	return gt.PrivateName
@@ -467,7 +474,7 @@ func (gt *Object) Error() error {
 	return gt.err
 }
 
-func defineInterfaces(ttype *Object, interfaces []*Interface) ([]*Interface, error) {
+func defineInterfaces(ttype Named, interfaces []*Interface) ([]*Interface, error) {
 	ifaces := []*Interface{}
 
 	if len(interfaces) == 0 {
@@ -481,6 +488,13 @@ func defineInterfaces(ttype *Object, interfaces []*Interface) ([]*Interface, err
 		if err != nil {
 			return ifaces, err
 		}
+		err = invariantf(
+			Named(iface) != ttype,
+			`%v cannot implement itself.`, ttype,
+		)
+		if err != nil {
+			return ifaces, err
+		}
 		if iface.ResolveType != nil {
 			err = invariantf(
 				iface.ResolveType != nil,
@@ -514,6 +528,9 @@ func defineFieldMap(ttype Named, fieldMap Fields) (FieldDefinitionMap, error) {
 		if field == nil {
 			continue
 		}
+		if field.err != nil {
+			return resultFieldMap, fmt.Errorf("%v.%v: %v", ttype, fieldName, field.err)
+		}
 		err = invariantf(
 			field.Type != nil,
 			`%v.%v field type must be Output Type but got: %v.`, ttype, fieldName, field.Type,
@@ -534,6 +551,8 @@ func defineFieldMap(ttype Named, fieldMap Fields) (FieldDefinitionMap, error) {
 			Resolve:           field.Resolve,
 			Subscribe:         field.Subscribe,
 			DeprecationReason: field.DeprecationReason,
+			AppliedDirectives: field.AppliedDirectives,
+			Timeout:           field.Timeout,
 		}
 
 		fieldDef.Args = []*Argument{}
@@ -558,6 +577,7 @@ func defineFieldMap(ttype Named, fieldMap Fields) (FieldDefinitionMap, error) {
 				PrivateDescription: arg.Description,
 				Type:               arg.Type,
 				DefaultValue:       arg.DefaultValue,
+				AppliedDirectives:  arg.AppliedDirectives,
 			}
 			fieldDef.Args = append(fieldDef.Args, fieldArg)
 		}
@@ -608,26 +628,39 @@ type Field struct {
 	Subscribe         FieldResolveFn      `json:"-"`
 	DeprecationReason string              `json:"deprecationReason"`
 	Description       string              `json:"description"`
+	AppliedDirectives []*AppliedDirective `json:"appliedDirectives"`
+
+	// Timeout, if set, bounds the time given to Resolve. A field whose resolver
+	// doesn't return in time fails with a FieldTimeoutError, while the rest of
+	// the response is still returned. The context of the resolver is canceled
+	// once the timeout expires or the resolver returns.
+	Timeout time.Duration `json:"-"`
+
+	// err is the error of the construction of the field, e.g. by TypedField
+	err error
 }
 
 type FieldConfigArgument []*ArgumentConfig
 
 type ArgumentConfig struct {
-	Name         string
-	Type         Input  `json:"type"`
-	DefaultValue any    `json:"defaultValue"`
-	Description  string `json:"description"`
+	Name              string
+	Type              Input               `json:"type"`
+	DefaultValue      any                 `json:"defaultValue"`
+	Description       string              `json:"description"`
+	AppliedDirectives []*AppliedDirective `json:"appliedDirectives"`
 }
 
 type FieldDefinitionMap map[string]*FieldDefinition
 type FieldDefinition struct {
-	Name              string         `json:"name"`
-	Description       string         `json:"description"`
-	Type              Output         `json:"type"`
-	Args              []*Argument    `json:"args"`
-	Resolve           FieldResolveFn `json:"-"`
-	Subscribe         FieldResolveFn `json:"-"`
-	DeprecationReason string         `json:"deprecationReason"`
+	Name              string              `json:"name"`
+	Description       string              `json:"description"`
+	Type              Output              `json:"type"`
+	Args              []*Argument         `json:"args"`
+	Resolve           FieldResolveFn      `json:"-"`
+	Subscribe         FieldResolveFn      `json:"-"`
+	DeprecationReason string              `json:"deprecationReason"`
+	AppliedDirectives []*AppliedDirective `json:"appliedDirectives"`
+	Timeout           time.Duration       `json:"-"`
 }
 
 type FieldArgument struct {
@@ -638,10 +671,11 @@ type FieldArgument struct {
 }
 
 type Argument struct {
-	PrivateName        string `json:"name"`
-	Type               Input  `json:"type"`
-	DefaultValue       any    `json:"defaultValue"`
-	PrivateDescription string `json:"description"`
+	PrivateName        string              `json:"name"`
+	Type               Input               `json:"type"`
+	DefaultValue       any                 `json:"defaultValue"`
+	PrivateDescription string              `json:"description"`
+	AppliedDirectives  []*AppliedDirective `json:"appliedDirectives"`
 }
 
 func (st *Argument) Name() string {
@@ -678,13 +712,16 @@ type Interface struct {
 	PrivateDescription string `json:"description"`
 	ResolveType        ResolveTypeFn
 
-	typeConfig        InterfaceConfig
-	initialisedFields bool
-	fields            FieldDefinitionMap
-	err               error
+	typeConfig            InterfaceConfig
+	initialisedFields     bool
+	fields                FieldDefinitionMap
+	initialisedInterfaces bool
+	interfaces            []*Interface
+	err                   error
 }
 type InterfaceConfig struct {
 	Name        string `json:"name"`
+	Interfaces  any    `json:"interfaces"`
 	Fields      any    `json:"fields"`
 	ResolveType ResolveTypeFn
 	Description string `json:"description"`
@@ -760,6 +797,31 @@ func (it *Interface) Fields() (fields FieldDefinitionMap) {
 	return it.fields
 }
 
+// Interfaces returns the interfaces implemented by this interface, which may
+// be given as a []*Interface or an InterfacesThunk.
+func (it *Interface) Interfaces() []*Interface {
+	if it.initialisedInterfaces {
+		return it.interfaces
+	}
+
+	var configInterfaces []*Interface
+	switch iface := it.typeConfig.Interfaces.(type) {
+	case InterfacesThunk:
+		configInterfaces = iface()
+	case []*Interface:
+		configInterfaces = iface
+	case nil:
+	default:
+		it.err = fmt.Errorf("Unknown Interface.Interfaces type: %T", it.typeConfig.Interfaces)
+		it.initialisedInterfaces = true
+		return nil
+	}
+
+	it.interfaces, it.err = defineInterfaces(it, configInterfaces)
+	it.initialisedInterfaces = true
+	return it.interfaces
+}
+
 func (it *Interface) String() string {
 	return it.PrivateName
 }
@@ -934,9 +996,10 @@ type Enum struct {
 }
 type EnumValueConfigMap map[string]*EnumValueConfig
 type EnumValueConfig struct {
-	Value             any    `json:"value"`
-	DeprecationReason string `json:"deprecationReason"`
-	Description       string `json:"description"`
+	Value             any                 `json:"value"`
+	DeprecationReason string              `json:"deprecationReason"`
+	Description       string              `json:"description"`
+	AppliedDirectives []*AppliedDirective `json:"appliedDirectives"`
 }
 type EnumConfig struct {
 	Name        string             `json:"name"`
@@ -944,10 +1007,11 @@ type EnumConfig struct {
 	Description string             `json:"description"`
 }
 type EnumValueDefinition struct {
-	Name              string `json:"name"`
-	Value             any    `json:"value"`
-	DeprecationReason string `json:"deprecationReason"`
-	Description       string `json:"description"`
+	Name              string              `json:"name"`
+	Value             any                 `json:"value"`
+	DeprecationReason string              `json:"deprecationReason"`
+	Description       string              `json:"description"`
+	AppliedDirectives []*AppliedDirective `json:"appliedDirectives"`
 }
 
 func NewEnum(config EnumConfig) *Enum {
@@ -993,6 +1057,7 @@ func (gt *Enum) defineEnumValues(valueMap EnumValueConfigMap) ([]*EnumValueDefin
 			Value:             valueConfig.Value,
 			DeprecationReason: valueConfig.DeprecationReason,
 			Description:       valueConfig.Description,
+			AppliedDirectives: valueConfig.AppliedDirectives,
 		}
 		if value.Value == nil {
 			value.Value = valueName
@@ -1104,15 +1169,17 @@ type InputObject struct {
 	err        error
 }
 type InputObjectFieldConfig struct {
-	Type         Input  `json:"type"`
-	DefaultValue any    `json:"defaultValue"`
-	Description  string `json:"description"`
+	Type              Input               `json:"type"`
+	DefaultValue      any                 `json:"defaultValue"`
+	Description       string              `json:"description"`
+	AppliedDirectives []*AppliedDirective `json:"appliedDirectives"`
 }
 type InputObjectField struct {
-	PrivateName        string `json:"name"`
-	Type               Input  `json:"type"`
-	DefaultValue       any    `json:"defaultValue"`
-	PrivateDescription string `json:"description"`
+	PrivateName        string              `json:"name"`
+	Type               Input               `json:"type"`
+	DefaultValue       any                 `json:"defaultValue"`
+	PrivateDescription string              `json:"description"`
+	AppliedDirectives  []*AppliedDirective `json:"appliedDirectives"`
 }
 
 func (st *InputObjectField) Name() string {
@@ -1135,6 +1202,11 @@ type InputObjectConfig struct {
 	Name        string `json:"name"`
 	Fields      any    `json:"fields"`
 	Description string `json:"description"`
+
+	// IsOneOf makes the input object a OneOf input object, for which exactly
+	// one field must be given a non-null value. Its fields must be nullable
+	// and have no default value.
+	IsOneOf bool `json:"isOneOf"`
 }
 
 func NewInputObject(config InputObjectConfig) *InputObject {
@@ -1182,11 +1254,27 @@ func (gt *InputObject) defineFieldMap() InputObjectFieldMap {
 		); gt.err != nil {
 			return resultFieldMap
 		}
+		if gt.typeConfig.IsOneOf {
+			_, nonNull := fieldConfig.Type.(*NonNull)
+			if gt.err = invariantf(
+				!nonNull,
+				`OneOf input field %v.%v must be nullable.`, gt, fieldName,
+			); gt.err != nil {
+				return resultFieldMap
+			}
+			if gt.err = invariantf(
+				fieldConfig.DefaultValue == nil,
+				`OneOf input field %v.%v cannot have a default value.`, gt, fieldName,
+			); gt.err != nil {
+				return resultFieldMap
+			}
+		}
 		field := &InputObjectField{}
 		field.PrivateName = fieldName
 		field.Type = fieldConfig.Type
 		field.PrivateDescription = fieldConfig.Description
 		field.DefaultValue = fieldConfig.DefaultValue
+		field.AppliedDirectives = fieldConfig.AppliedDirectives
 		resultFieldMap[fieldName] = field
 	}
 	gt.init = true
@@ -1214,6 +1302,11 @@ func (gt *InputObject) Fields() InputObjectFieldMap {
 func (gt *InputObject) Name() string {
 	return gt.PrivateName
 }
+
+// IsOneOf reports whether the input object is a OneOf input object.
+func (gt *InputObject) IsOneOf() bool {
+	return gt.typeConfig.IsOneOf
+}
 func (gt *InputObject) Description() string {
 	return gt.PrivateDescription
 }
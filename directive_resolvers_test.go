@@ -0,0 +1,152 @@
+package graphql_test
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"reflect"
+	"strings"
+	"testing"
+
+	"github.com/dagger/graphql"
+	"github.com/dagger/graphql/testutil"
+)
+
+var uppercaseDirective = graphql.NewDirective(graphql.DirectiveConfig{
+	Name:      "uppercase",
+	Locations: []string{graphql.DirectiveLocationField, graphql.DirectiveLocationFieldDefinition},
+	Resolve: func(args map[string]any, next graphql.FieldResolveFn) graphql.FieldResolveFn {
+		return func(p graphql.ResolveParams) (any, error) {
+			result, err := next(p)
+			if s, ok := result.(string); ok {
+				return strings.ToUpper(s), err
+			}
+			return result, err
+		}
+	},
+})
+
+var suffixDirective = graphql.NewDirective(graphql.DirectiveConfig{
+	Name:      "suffix",
+	Locations: []string{graphql.DirectiveLocationField},
+	Args: graphql.FieldConfigArgument{
+		&graphql.ArgumentConfig{
+			Name:         "with",
+			Type:         graphql.String,
+			DefaultValue: "!",
+		},
+	},
+	Resolve: func(args map[string]any, next graphql.FieldResolveFn) graphql.FieldResolveFn {
+		return func(p graphql.ResolveParams) (any, error) {
+			result, err := next(p)
+			return fmt.Sprintf("%v%v", result, args["with"]), err
+		}
+	},
+})
+
+var authDirective = graphql.NewDirective(graphql.DirectiveConfig{
+	Name:      "auth",
+	Locations: []string{graphql.DirectiveLocationFieldDefinition},
+	Args: graphql.FieldConfigArgument{
+		&graphql.ArgumentConfig{
+			Name: "role",
+			Type: graphql.NewNonNull(graphql.String),
+		},
+	},
+	Resolve: func(args map[string]any, next graphql.FieldResolveFn) graphql.FieldResolveFn {
+		return func(p graphql.ResolveParams) (any, error) {
+			if role, _ := p.Context.Value(roleKey{}).(string); role != args["role"] {
+				return nil, errors.New("forbidden")
+			}
+			return next(p)
+		}
+	},
+})
+
+type roleKey struct{}
+
+func directiveResolversSchema(t *testing.T) graphql.Schema {
+	schema, err := graphql.NewSchema(graphql.SchemaConfig{
+		Query: graphql.NewObject(graphql.ObjectConfig{
+			Name: "Query",
+			Fields: graphql.Fields{
+				"greeting": &graphql.Field{
+					Type: graphql.String,
+					Resolve: func(p graphql.ResolveParams) (any, error) {
+						return "hello", nil
+					},
+				},
+				"secret": &graphql.Field{
+					Type: graphql.String,
+					AppliedDirectives: []*graphql.AppliedDirective{
+						{Name: "auth", Args: map[string]any{"role": "admin"}},
+						{Name: "uppercase"},
+					},
+					Resolve: func(p graphql.ResolveParams) (any, error) {
+						return "s3cr3t", nil
+					},
+				},
+			},
+		}),
+		Directives: append([]*graphql.Directive{
+			uppercaseDirective,
+			suffixDirective,
+			authDirective,
+		}, graphql.SpecifiedDirectives...),
+	})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	return schema
+}
+
+func TestDirectiveResolvers_WrapResolversOfQueryFields(t *testing.T) {
+	result := graphql.Do(graphql.Params{
+		Schema: directiveResolversSchema(t),
+		RequestString: `query ($with: String) {
+			plain: greeting
+			upper: greeting @uppercase
+			suffixed: greeting @suffix
+			both: greeting @suffix(with: $with) @uppercase
+		}`,
+		VariableValues: map[string]any{"with": "?"},
+	})
+	expected := &graphql.Result{
+		Data: map[string]any{
+			"plain":    "hello",
+			"upper":    "HELLO",
+			"suffixed": "hello!",
+			"both":     "HELLO?",
+		},
+	}
+	if !reflect.DeepEqual(expected, result) {
+		t.Fatalf("Unexpected result, Diff: %v", testutil.Diff(expected, result))
+	}
+}
+
+func TestDirectiveResolvers_WrapResolversOfDefinitionFields(t *testing.T) {
+	schema := directiveResolversSchema(t)
+
+	result := graphql.Do(graphql.Params{
+		Schema:        schema,
+		RequestString: `{ secret }`,
+		Context:       context.WithValue(context.Background(), roleKey{}, "admin"),
+	})
+	expected := &graphql.Result{
+		Data: map[string]any{
+			"secret": "S3CR3T",
+		},
+	}
+	if !reflect.DeepEqual(expected, result) {
+		t.Fatalf("Unexpected result, Diff: %v", testutil.Diff(expected, result))
+	}
+
+	result = graphql.Do(graphql.Params{
+		Schema:        schema,
+		RequestString: `{ secret @suffix }`,
+		Context:       context.WithValue(context.Background(), roleKey{}, "guest"),
+	})
+	if len(result.Errors) != 1 || result.Errors[0].Message != "forbidden" {
+		t.Fatalf("expected a forbidden error, got: %v", result.Errors)
+	}
+}
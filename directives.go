@@ -32,25 +32,57 @@ var SpecifiedDirectives = []*Directive{
 	IncludeDirective,
 	SkipDirective,
 	DeprecatedDirective,
+	OneOfDirective,
+}
+
+// IncrementalDirectives The @defer and @stream directives, which are not part of
+// the specified directives yet. Add them to SchemaConfig.Directives to allow
+// them in queries executed with ExecuteIncremental.
+var IncrementalDirectives = []*Directive{
+	DeferDirective,
+	StreamDirective,
 }
 
 // Directive structs are used by the GraphQL runtime as a way of modifying execution
 // behavior. Type system creators will usually not create these directly.
 type Directive struct {
-	Name        string      `json:"name"`
-	Description string      `json:"description"`
-	Locations   []string    `json:"locations"`
-	Args        []*Argument `json:"args"`
+	Name         string             `json:"name"`
+	Description  string             `json:"description"`
+	Locations    []string           `json:"locations"`
+	Args         []*Argument        `json:"args"`
+	IsRepeatable bool               `json:"isRepeatable"`
+	Resolve      DirectiveResolveFn `json:"-"`
 
 	err error
 }
 
+// DirectiveResolveFn wraps the resolver of a field the directive is applied
+// to, either in the query or on the field definition. It is given the
+// coerced arguments of the directive and the inner resolver, and returns the
+// resolver to use instead.
+type DirectiveResolveFn func(args map[string]any, next FieldResolveFn) FieldResolveFn
+
 // DirectiveConfig options for creating a new GraphQLDirective
 type DirectiveConfig struct {
 	Name        string              `json:"name"`
 	Description string              `json:"description"`
 	Locations   []string            `json:"locations"`
 	Args        FieldConfigArgument `json:"args"`
+
+	// IsRepeatable allows the directive to be used several times at the same
+	// location.
+	IsRepeatable bool `json:"isRepeatable"`
+
+	// Resolve wraps the resolvers of the fields the directive is applied to.
+	Resolve DirectiveResolveFn `json:"-"`
+}
+
+// AppliedDirective is a directive applied to an element of a schema, such as
+// @key(fields: "id") on an object type. Applied directives are metadata for
+// executors and printers, its arguments are given as Go values.
+type AppliedDirective struct {
+	Name string         `json:"name"`
+	Args map[string]any `json:"args"`
 }
 
 func NewDirective(config DirectiveConfig) *Directive {
@@ -89,6 +121,8 @@ func NewDirective(config DirectiveConfig) *Directive {
 	dir.Description = config.Description
 	dir.Locations = config.Locations
 	dir.Args = args
+	dir.IsRepeatable = config.IsRepeatable
+	dir.Resolve = config.Resolve
 	return dir
 }
 
@@ -149,3 +183,66 @@ var DeprecatedDirective = NewDirective(DirectiveConfig{
 		DirectiveLocationEnumValue,
 	},
 })
+
+// OneOfDirective Used to declare an input object as a OneOf input object.
+var OneOfDirective = NewDirective(DirectiveConfig{
+	Name: "oneOf",
+	Description: "Indicates exactly one field must be supplied and this field must not " +
+		"be `null`.",
+	Locations: []string{
+		DirectiveLocationInputObject,
+	},
+})
+
+// DeferDirective Used to defer the delivery of a fragment to a subsequent payload.
+var DeferDirective = NewDirective(DirectiveConfig{
+	Name: "defer",
+	Description: "Directs the executor to deliver this fragment in a subsequent payload " +
+		"when the `if` argument is true.",
+	Args: FieldConfigArgument{
+		&ArgumentConfig{
+			Name:         "if",
+			Type:         Boolean,
+			Description:  "Deferred when true.",
+			DefaultValue: true,
+		},
+		&ArgumentConfig{
+			Name:        "label",
+			Type:        String,
+			Description: "Unique name identifying the payload of this fragment.",
+		},
+	},
+	Locations: []string{
+		DirectiveLocationFragmentSpread,
+		DirectiveLocationInlineFragment,
+	},
+})
+
+// StreamDirective Used to deliver the items of a list field in subsequent payloads.
+var StreamDirective = NewDirective(DirectiveConfig{
+	Name: "stream",
+	Description: "Directs the executor to deliver the items of this list field past " +
+		"`initialCount` in subsequent payloads when the `if` argument is true.",
+	Args: FieldConfigArgument{
+		&ArgumentConfig{
+			Name:         "if",
+			Type:         Boolean,
+			Description:  "Streamed when true.",
+			DefaultValue: true,
+		},
+		&ArgumentConfig{
+			Name:        "label",
+			Type:        String,
+			Description: "Unique name identifying the payloads of this field.",
+		},
+		&ArgumentConfig{
+			Name:         "initialCount",
+			Type:         Int,
+			Description:  "Number of items delivered in the initial payload.",
+			DefaultValue: 0,
+		},
+	},
+	Locations: []string{
+		DirectiveLocationField,
+	},
+})
@@ -0,0 +1,103 @@
+package graphql
+
+import (
+	"container/list"
+	"strconv"
+	"sync"
+	"sync/atomic"
+
+	"github.com/dagger/graphql/gqlerrors"
+	"github.com/dagger/graphql/language/ast"
+)
+
+// CachedDocument is a document parsed and validated by Do.
+type CachedDocument struct {
+	AST *ast.Document
+
+	// ValidationErrors are the errors of the validation of the document,
+	// empty if it is valid.
+	ValidationErrors []gqlerrors.FormattedError
+}
+
+// DocumentCache caches the documents parsed and validated by Do, so that
+// identical requests skip parsing and validation. Keys are opaque, and derived
+// from both the request string and the schema. Implementations must be safe
+// for concurrent use, and must not modify cached documents.
+type DocumentCache interface {
+	Get(key string) (*CachedDocument, bool)
+	Add(key string, doc *CachedDocument)
+}
+
+var schemaIDs uint64
+
+func nextSchemaID() uint64 {
+	return atomic.AddUint64(&schemaIDs, 1)
+}
+
+func documentCacheKey(schema *Schema, requestString string) string {
+	return strconv.FormatUint(schema.id, 10) + ":" + requestString
+}
+
+// LRUDocumentCache is a DocumentCache holding up to a fixed number of
+// documents, evicting the least recently used ones first.
+type LRUDocumentCache struct {
+	size int
+
+	mu      sync.Mutex
+	entries map[string]*list.Element
+	order   *list.List
+}
+
+type lruDocumentCacheEntry struct {
+	key string
+	doc *CachedDocument
+}
+
+// NewLRUDocumentCache creates an LRUDocumentCache holding up to size
+// documents.
+func NewLRUDocumentCache(size int) *LRUDocumentCache {
+	return &LRUDocumentCache{
+		size:    size,
+		entries: map[string]*list.Element{},
+		order:   list.New(),
+	}
+}
+
+// Get implements DocumentCache.
+func (c *LRUDocumentCache) Get(key string) (*CachedDocument, bool) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	elem, ok := c.entries[key]
+	if !ok {
+		return nil, false
+	}
+	c.order.MoveToFront(elem)
+	return elem.Value.(*lruDocumentCacheEntry).doc, true
+}
+
+// Add implements DocumentCache.
+func (c *LRUDocumentCache) Add(key string, doc *CachedDocument) {
+	if c.size <= 0 {
+		return
+	}
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	if elem, ok := c.entries[key]; ok {
+		elem.Value.(*lruDocumentCacheEntry).doc = doc
+		c.order.MoveToFront(elem)
+		return
+	}
+	c.entries[key] = c.order.PushFront(&lruDocumentCacheEntry{key: key, doc: doc})
+	for c.order.Len() > c.size {
+		oldest := c.order.Back()
+		c.order.Remove(oldest)
+		delete(c.entries, oldest.Value.(*lruDocumentCacheEntry).key)
+	}
+}
+
+// Len returns the number of cached documents.
+func (c *LRUDocumentCache) Len() int {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	return c.order.Len()
+}
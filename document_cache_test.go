@@ -0,0 +1,113 @@
+package graphql_test
+
+import (
+	"context"
+	"reflect"
+	"testing"
+
+	"github.com/dagger/graphql"
+	"github.com/dagger/graphql/gqlerrors"
+	"github.com/dagger/graphql/testutil"
+)
+
+type countingDocumentCache struct {
+	*graphql.LRUDocumentCache
+	hits int
+	adds int
+}
+
+func (c *countingDocumentCache) Get(key string) (*graphql.CachedDocument, bool) {
+	doc, ok := c.LRUDocumentCache.Get(key)
+	if ok {
+		c.hits++
+	}
+	return doc, ok
+}
+
+func (c *countingDocumentCache) Add(key string, doc *graphql.CachedDocument) {
+	c.adds++
+	c.LRUDocumentCache.Add(key, doc)
+}
+
+func TestDocumentCache_ReusesDocumentsAndNotifiesExtensions(t *testing.T) {
+	cache := &countingDocumentCache{LRUDocumentCache: graphql.NewLRUDocumentCache(10)}
+	parses, validations := 0, 0
+	ext := newtestExt("testExt")
+	ext.parseDidStartFn = func(ctx context.Context) (context.Context, graphql.ParseFinishFunc) {
+		parses++
+		return ctx, func(err error) {}
+	}
+	ext.validationDidStartFn = func(ctx context.Context) (context.Context, graphql.ValidationFinishFunc) {
+		validations++
+		return ctx, func([]gqlerrors.FormattedError) {}
+	}
+	schema := tinit(t)
+	schema.AddExtensions(ext)
+
+	expected := &graphql.Result{
+		Data: map[string]any{"a": "foo"},
+	}
+	for i := 0; i < 3; i++ {
+		result := graphql.Do(graphql.Params{
+			Schema:        schema,
+			RequestString: `{ a }`,
+			DocumentCache: cache,
+		})
+		if !reflect.DeepEqual(expected, result) {
+			t.Fatalf("Unexpected result, Diff: %v", testutil.Diff(expected, result))
+		}
+	}
+	if cache.adds != 1 || cache.hits != 2 {
+		t.Fatalf("expected 1 add and 2 hits, got %d adds and %d hits", cache.adds, cache.hits)
+	}
+	if parses != 3 || validations != 3 {
+		t.Fatalf("expected extensions to be notified 3 times, got %d parses and %d validations", parses, validations)
+	}
+}
+
+func TestDocumentCache_CachesValidationErrors(t *testing.T) {
+	base := tinit(t)
+	schema, err := graphql.NewSchema(graphql.SchemaConfig{
+		Query:         base.QueryType(),
+		DocumentCache: graphql.NewLRUDocumentCache(10),
+	})
+	if err != nil {
+		t.Fatalf("Error in schema %v", err.Error())
+	}
+	first := graphql.Do(graphql.Params{Schema: schema, RequestString: `{ b }`})
+	second := graphql.Do(graphql.Params{Schema: schema, RequestString: `{ b }`})
+	if len(first.Errors) != 1 {
+		t.Fatalf("expected a validation error, got %v", first.Errors)
+	}
+	if !reflect.DeepEqual(first, second) {
+		t.Fatalf("Unexpected result, Diff: %v", testutil.Diff(first, second))
+	}
+}
+
+func TestDocumentCache_KeysBySchema(t *testing.T) {
+	cache := graphql.NewLRUDocumentCache(10)
+	graphql.Do(graphql.Params{Schema: tinit(t), RequestString: `{ a }`, DocumentCache: cache})
+	graphql.Do(graphql.Params{Schema: tinit(t), RequestString: `{ a }`, DocumentCache: cache})
+	if cache.Len() != 2 {
+		t.Fatalf("expected a document per schema, got %d", cache.Len())
+	}
+}
+
+func TestLRUDocumentCache_EvictsLeastRecentlyUsed(t *testing.T) {
+	cache := graphql.NewLRUDocumentCache(2)
+	a, b, c := &graphql.CachedDocument{}, &graphql.CachedDocument{}, &graphql.CachedDocument{}
+	cache.Add("a", a)
+	cache.Add("b", b)
+	cache.Get("a")
+	cache.Add("c", c)
+
+	if _, ok := cache.Get("b"); ok {
+		t.Fatalf("expected b to be evicted")
+	}
+	if doc, ok := cache.Get("a"); !ok || doc != a {
+		t.Fatalf("expected a to be cached")
+	}
+	if doc, ok := cache.Get("c"); !ok || doc != c {
+		t.Fatalf("expected c to be cached")
+	}
+}
@@ -206,9 +206,18 @@ func TestTypeSystem_EnumValues_DoesNotAcceptIncorrectInternalValue(t *testing.T)
 		Data: map[string]any{
 			"colorEnum": nil,
 		},
+		Errors: []gqlerrors.FormattedError{
+			{
+				Message: "Cannot serialize value as Color: Enum Color cannot represent value: string(GREEN)",
+				Locations: []location.SourceLocation{
+					{Line: 1, Column: 3},
+				},
+				Path: []any{"colorEnum"},
+			},
+		},
 	}
 	result := executeEnumTypeTest(t, query)
-	if !reflect.DeepEqual(expected, result) {
+	if !testutil.EqualResults(expected, result) {
 		t.Fatalf("Unexpected result, Diff: %v", testutil.Diff(expected, result))
 	}
 }
@@ -7,6 +7,8 @@ import (
 	"reflect"
 	"sort"
 	"strings"
+	"sync"
+	"time"
 
 	"github.com/dagger/graphql/gqlerrors"
 	"github.com/dagger/graphql/language/ast"
@@ -22,6 +24,19 @@ type ExecuteParams struct {
 	// Context may be provided to pass application-specific per-request
 	// information to resolve functions.
 	Context context.Context
+
+	// OrderedResults makes the executor return selection sets as *OrderedMap
+	// values instead of map[string]any, so that the result serializes with
+	// fields in the order they were requested.
+	OrderedResults bool
+
+	// Parallelism is the maximum number of sibling thunks resolved
+	// concurrently at each depth of a query result. Zero or one resolves them
+	// one at a time. Mutations are always resolved serially.
+	Parallelism int
+
+	// incremental is set by ExecuteIncremental to collect deferred work.
+	incremental *incrementalState
 }
 
 func Execute(p ExecuteParams) (result *Result) {
@@ -60,13 +75,16 @@ func Execute(p ExecuteParams) (result *Result) {
 		}()
 
 		exeContext, err := buildExecutionContext(buildExecutionCtxParams{
-			Schema:        p.Schema,
-			Root:          p.Root,
-			AST:           p.AST,
-			OperationName: p.OperationName,
-			Args:          p.Args,
-			Result:        result,
-			Context:       p.Context,
+			Schema:         p.Schema,
+			Root:           p.Root,
+			AST:            p.AST,
+			OperationName:  p.OperationName,
+			Args:           p.Args,
+			Result:         result,
+			Context:        p.Context,
+			OrderedResults: p.OrderedResults,
+			Parallelism:    p.Parallelism,
+			incremental:    p.incremental,
 		})
 
 		if err != nil {
@@ -93,13 +111,16 @@ func Execute(p ExecuteParams) (result *Result) {
 }
 
 type buildExecutionCtxParams struct {
-	Schema        Schema
-	Root          any
-	AST           *ast.Document
-	OperationName string
-	Args          map[string]any
-	Result        *Result
-	Context       context.Context
+	Schema         Schema
+	Root           any
+	AST            *ast.Document
+	OperationName  string
+	Args           map[string]any
+	Result         *Result
+	Context        context.Context
+	OrderedResults bool
+	Parallelism    int
+	incremental    *incrementalState
 }
 
 type executionContext struct {
@@ -110,6 +131,23 @@ type executionContext struct {
 	VariableValues map[string]any
 	Errors         []gqlerrors.FormattedError
 	Context        context.Context
+	OrderedResults bool
+	Parallelism    int
+	Loaders        *LoaderRegistry
+
+	// incremental is set when executing with ExecuteIncremental, in which case
+	// @defer and @stream are honored instead of ignored.
+	incremental *incrementalState
+
+	// errorsMu guards Errors, which may be appended to from several
+	// goroutines when thunks are resolved in parallel.
+	errorsMu sync.Mutex
+}
+
+func (eCtx *executionContext) addErrors(errs ...gqlerrors.FormattedError) {
+	eCtx.errorsMu.Lock()
+	defer eCtx.errorsMu.Unlock()
+	eCtx.Errors = append(eCtx.Errors, errs...)
 }
 
 func buildExecutionContext(p buildExecutionCtxParams) (*executionContext, error) {
@@ -149,12 +187,27 @@ func buildExecutionContext(p buildExecutionCtxParams) (*executionContext, error)
 		return nil, err
 	}
 
+	// Provide a request-scoped loader registry, unless the caller brought one.
+	ctx := p.Context
+	if ctx == nil {
+		ctx = context.Background()
+	}
+	loaders := LoaderRegistryFromContext(ctx)
+	if loaders == nil {
+		loaders = NewLoaderRegistry()
+		ctx = ContextWithLoaderRegistry(ctx, loaders)
+	}
+
 	eCtx.Schema = p.Schema
 	eCtx.Fragments = fragments
 	eCtx.Root = p.Root
 	eCtx.Operation = operation
 	eCtx.VariableValues = variableValues
-	eCtx.Context = p.Context
+	eCtx.Context = ctx
+	eCtx.Loaders = loaders
+	eCtx.incremental = p.incremental
+	eCtx.OrderedResults = p.OrderedResults
+	eCtx.Parallelism = p.Parallelism
 	return eCtx, nil
 }
 
@@ -170,14 +223,18 @@ func executeOperation(p executeOperationParams) *Result {
 		return &Result{Errors: gqlerrors.FormatErrors(err)}
 	}
 
+	deferred := []*deferredFragment{}
 	fields := collectFields(collectFieldsParams{
 		ExeContext:   p.ExecutionContext,
 		RuntimeType:  operationType,
 		SelectionSet: p.Operation.GetSelectionSet(),
+		Deferred:     &deferred,
 	})
+	p.ExecutionContext.deferFragments(p.ExecutionContext.Context, deferred, p.Root, nil)
 
 	executeFieldsParams := executeFieldsParams{
 		ExecutionContext: p.ExecutionContext,
+		Context:          p.ExecutionContext.Context,
 		ParentType:       operationType,
 		Source:           p.Root,
 		Fields:           fields,
@@ -239,10 +296,13 @@ func getOperationRootType(schema Schema, operation ast.Definition) (*Object, err
 
 type executeFieldsParams struct {
 	ExecutionContext *executionContext
-	ParentType       *Object
-	Source           any
-	Fields           map[string][]*ast.Field
-	Path             *ResponsePath
+	// Context is the context the fields are resolved with: that of the field
+	// of the parent object, or that of the execution for root fields.
+	Context    context.Context
+	ParentType *Object
+	Source     any
+	Fields     map[string][]*ast.Field
+	Path       *ResponsePath
 }
 
 // Implements the "Evaluating selection sets" section of the spec for "write" mode.
@@ -254,18 +314,18 @@ func executeFieldsSerially(p executeFieldsParams) *Result {
 		p.Fields = map[string][]*ast.Field{}
 	}
 
-	finalResults := make(map[string]any, len(p.Fields))
+	finalResults := newResultMap(p.ExecutionContext, len(p.Fields))
 	for _, orderedField := range orderedFields(p.Fields) {
 		responseName := orderedField.responseName
 		fieldASTs := orderedField.fieldASTs
 		fieldPath := p.Path.WithKey(responseName)
-		resolved, state := resolveField(p.ExecutionContext, p.ParentType, p.Source, fieldASTs, fieldPath)
+		resolved, state := resolveField(p.ExecutionContext, p.Context, p.ParentType, p.Source, fieldASTs, fieldPath)
 		if state.hasNoFieldDefs {
 			continue
 		}
-		finalResults[responseName] = resolved
+		setResult(finalResults, responseName, resolved)
 	}
-	dethunkMapDepthFirst(finalResults)
+	dethunkDepthFirst(finalResults)
 
 	return &Result{
 		Data:   finalResults,
@@ -277,7 +337,7 @@ func executeFieldsSerially(p executeFieldsParams) *Result {
 func executeFields(p executeFieldsParams) *Result {
 	finalResults := executeSubFields(p)
 
-	dethunkMapWithBreadthFirstTraversal(finalResults)
+	dethunkResults(p.ExecutionContext, finalResults)
 
 	return &Result{
 		Data:   finalResults,
@@ -285,7 +345,7 @@ func executeFields(p executeFieldsParams) *Result {
 	}
 }
 
-func executeSubFields(p executeFieldsParams) map[string]any {
+func executeSubFields(p executeFieldsParams) any {
 
 	if p.Source == nil {
 		p.Source = map[string]any{}
@@ -294,19 +354,39 @@ func executeSubFields(p executeFieldsParams) map[string]any {
 		p.Fields = map[string][]*ast.Field{}
 	}
 
-	finalResults := make(map[string]any, len(p.Fields))
-	for responseName, fieldASTs := range p.Fields {
+	finalResults := newResultMap(p.ExecutionContext, len(p.Fields))
+	for _, orderedField := range orderedFields(p.Fields) {
+		responseName := orderedField.responseName
 		fieldPath := p.Path.WithKey(responseName)
-		resolved, state := resolveField(p.ExecutionContext, p.ParentType, p.Source, fieldASTs, fieldPath)
+		resolved, state := resolveField(p.ExecutionContext, p.Context, p.ParentType, p.Source, orderedField.fieldASTs, fieldPath)
 		if state.hasNoFieldDefs {
 			continue
 		}
-		finalResults[responseName] = resolved
+		setResult(finalResults, responseName, resolved)
 	}
 
 	return finalResults
 }
 
+// newResultMap returns the value used to collect the results of a selection
+// set: an *OrderedMap if ordered results were requested, a map[string]any
+// otherwise.
+func newResultMap(eCtx *executionContext, size int) any {
+	if eCtx.OrderedResults {
+		return NewOrderedMap(size)
+	}
+	return make(map[string]any, size)
+}
+
+func setResult(results any, responseName string, value any) {
+	switch results := results.(type) {
+	case *OrderedMap:
+		results.Set(responseName, value)
+	case map[string]any:
+		results[responseName] = value
+	}
+}
+
 // dethunkQueue is a structure that allows us to execute a classic breadth-first traversal.
 type dethunkQueue struct {
 	DethunkFuncs []func()
@@ -316,22 +396,47 @@ func (d *dethunkQueue) push(f func()) {
 	d.DethunkFuncs = append(d.DethunkFuncs, f)
 }
 
-func (d *dethunkQueue) shift() func() {
-	f := d.DethunkFuncs[0]
-	d.DethunkFuncs = d.DethunkFuncs[1:]
-	return f
+// dethunkResults replaces the thunks found in results with their values, breadth-first.
+func dethunkResults(eCtx *executionContext, results any) {
+	if eCtx.Parallelism > 1 {
+		dethunkWithParallelBreadthFirstTraversal(eCtx, results)
+	} else {
+		dethunkMapWithBreadthFirstTraversal(eCtx, results)
+	}
 }
 
 // dethunkWithBreadthFirstTraversal performs a breadth-first descent of the map, calling any thunks
 // in the map values and replacing each thunk with that thunk's return value. This parallels
 // the reference graphql-js implementation, which calls Promise.all on thunks at each depth (which
 // is an implicit parallel descent).
-func dethunkMapWithBreadthFirstTraversal(finalResults map[string]any) {
+//
+// The queue is drained one depth at a time. Every resolver of a depth has run before its
+// thunks are called, so the keys they requested from loaders are dispatched in one batch
+// beforehand.
+func dethunkMapWithBreadthFirstTraversal(eCtx *executionContext, finalResults any) {
 	dethunkQueue := &dethunkQueue{DethunkFuncs: []func(){}}
-	dethunkMapBreadthFirst(finalResults, dethunkQueue)
+	eCtx.Loaders.Dispatch(eCtx.Context)
+	dethunkBreadthFirst(finalResults, dethunkQueue)
 	for len(dethunkQueue.DethunkFuncs) > 0 {
-		f := dethunkQueue.shift()
-		f()
+		eCtx.Loaders.Dispatch(eCtx.Context)
+		level := dethunkQueue.DethunkFuncs
+		dethunkQueue.DethunkFuncs = []func(){}
+		for _, f := range level {
+			f()
+		}
+	}
+}
+
+// dethunkBreadthFirst dethunks a selection set result, which is either a
+// map[string]any or an *OrderedMap, or a list of results.
+func dethunkBreadthFirst(results any, dethunkQueue *dethunkQueue) {
+	switch results := results.(type) {
+	case map[string]any:
+		dethunkMapBreadthFirst(results, dethunkQueue)
+	case *OrderedMap:
+		dethunkOrderedMapBreadthFirst(results, dethunkQueue)
+	case []any:
+		dethunkListBreadthFirst(results, dethunkQueue)
 	}
 }
 
@@ -340,12 +445,16 @@ func dethunkMapBreadthFirst(m map[string]any, dethunkQueue *dethunkQueue) {
 		if f, ok := v.(func() any); ok {
 			m[k] = f()
 		}
-		switch val := m[k].(type) {
-		case map[string]any:
-			dethunkQueue.push(func() { dethunkMapBreadthFirst(val, dethunkQueue) })
-		case []any:
-			dethunkQueue.push(func() { dethunkListBreadthFirst(val, dethunkQueue) })
+		pushDethunkBreadthFirst(m[k], dethunkQueue)
+	}
+}
+
+func dethunkOrderedMapBreadthFirst(m *OrderedMap, dethunkQueue *dethunkQueue) {
+	for _, k := range m.keys {
+		if f, ok := m.values[k].(func() any); ok {
+			m.values[k] = f()
 		}
+		pushDethunkBreadthFirst(m.values[k], dethunkQueue)
 	}
 }
 
@@ -354,30 +463,155 @@ func dethunkListBreadthFirst(list []any, dethunkQueue *dethunkQueue) {
 		if f, ok := v.(func() any); ok {
 			list[i] = f()
 		}
-		switch val := list[i].(type) {
-		case map[string]any:
-			dethunkQueue.push(func() { dethunkMapBreadthFirst(val, dethunkQueue) })
-		case []any:
-			dethunkQueue.push(func() { dethunkListBreadthFirst(val, dethunkQueue) })
+		pushDethunkBreadthFirst(list[i], dethunkQueue)
+	}
+}
+
+func pushDethunkBreadthFirst(value any, dethunkQueue *dethunkQueue) {
+	switch val := value.(type) {
+	case map[string]any, *OrderedMap:
+		dethunkQueue.push(func() { dethunkBreadthFirst(val, dethunkQueue) })
+	case []any:
+		dethunkQueue.push(func() { dethunkListBreadthFirst(val, dethunkQueue) })
+	}
+}
+
+// dethunkWithParallelBreadthFirstTraversal performs the same breadth-first descent as
+// dethunkMapWithBreadthFirstTraversal, but calls the thunks found at each depth concurrently,
+// using at most eCtx.Parallelism goroutines. Results are written back by the calling goroutine
+// once every thunk of the depth has returned.
+func dethunkWithParallelBreadthFirstTraversal(eCtx *executionContext, finalResults any) {
+	level := []any{finalResults}
+	for len(level) > 0 {
+		var (
+			thunks []func() any
+			setFns []func(any)
+		)
+		for _, container := range level {
+			eachResultValue(container, func(value any, set func(any)) {
+				if f, ok := value.(func() any); ok {
+					thunks = append(thunks, f)
+					setFns = append(setFns, set)
+				}
+			})
+		}
+
+		eCtx.Loaders.Dispatch(eCtx.Context)
+		for i, value := range callThunksInParallel(thunks, eCtx.Parallelism) {
+			setFns[i](value)
+		}
+
+		next := []any{}
+		for _, container := range level {
+			eachResultValue(container, func(value any, set func(any)) {
+				switch value.(type) {
+				case map[string]any, *OrderedMap, []any:
+					next = append(next, value)
+				}
+			})
+		}
+		level = next
+	}
+}
+
+// eachResultValue calls fn for every value held by a map[string]any, *OrderedMap
+// or []any, along with a function replacing that value.
+func eachResultValue(container any, fn func(value any, set func(any))) {
+	switch container := container.(type) {
+	case map[string]any:
+		for k, v := range container {
+			k := k
+			fn(v, func(value any) { container[k] = value })
+		}
+	case *OrderedMap:
+		for _, k := range container.keys {
+			k := k
+			fn(container.values[k], func(value any) { container.values[k] = value })
+		}
+	case []any:
+		for i, v := range container {
+			i := i
+			fn(v, func(value any) { container[i] = value })
 		}
 	}
 }
 
-// dethunkMapDepthFirst performs a serial descent of the map, calling any thunks
+// callThunksInParallel calls the given thunks on a pool of at most parallelism goroutines
+// and returns their results in order. A panic raised by a thunk, such as a null propagating
+// from a non-null field, is re-raised in the calling goroutine.
+func callThunksInParallel(thunks []func() any, parallelism int) []any {
+	results := make([]any, len(thunks))
+	if len(thunks) == 0 {
+		return results
+	}
+	if parallelism > len(thunks) {
+		parallelism = len(thunks)
+	}
+
+	var (
+		wg        sync.WaitGroup
+		panicOnce sync.Once
+		panicVal  any
+	)
+	indexes := make(chan int)
+	for w := 0; w < parallelism; w++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for i := range indexes {
+				func() {
+					defer func() {
+						if r := recover(); r != nil {
+							panicOnce.Do(func() { panicVal = r })
+						}
+					}()
+					results[i] = thunks[i]()
+				}()
+			}
+		}()
+	}
+	for i := range thunks {
+		indexes <- i
+	}
+	close(indexes)
+	wg.Wait()
+
+	if panicVal != nil {
+		panic(panicVal)
+	}
+	return results
+}
+
+// dethunkDepthFirst performs a serial descent of the results, calling any thunks
 // in the map values and replacing each thunk with that thunk's return value. This is needed
 // to conform to the graphql-js reference implementation, which requires serial (depth-first)
 // implementations for mutation selects.
+func dethunkDepthFirst(results any) {
+	switch results := results.(type) {
+	case map[string]any:
+		dethunkMapDepthFirst(results)
+	case *OrderedMap:
+		dethunkOrderedMapDepthFirst(results)
+	case []any:
+		dethunkListDepthFirst(results)
+	}
+}
+
 func dethunkMapDepthFirst(m map[string]any) {
 	for k, v := range m {
 		if f, ok := v.(func() any); ok {
 			m[k] = f()
 		}
-		switch val := m[k].(type) {
-		case map[string]any:
-			dethunkMapDepthFirst(val)
-		case []any:
-			dethunkListDepthFirst(val)
+		dethunkDepthFirst(m[k])
+	}
+}
+
+func dethunkOrderedMapDepthFirst(m *OrderedMap) {
+	for _, k := range m.keys {
+		if f, ok := m.values[k].(func() any); ok {
+			m.values[k] = f()
 		}
+		dethunkDepthFirst(m.values[k])
 	}
 }
 
@@ -386,12 +620,7 @@ func dethunkListDepthFirst(list []any) {
 		if f, ok := v.(func() any); ok {
 			list[i] = f()
 		}
-		switch val := list[i].(type) {
-		case map[string]any:
-			dethunkMapDepthFirst(val)
-		case []any:
-			dethunkListDepthFirst(val)
-		}
+		dethunkDepthFirst(list[i])
 	}
 }
 
@@ -401,6 +630,10 @@ type collectFieldsParams struct {
 	SelectionSet         *ast.SelectionSet
 	Fields               map[string][]*ast.Field
 	VisitedFragmentNames map[string]bool
+
+	// Deferred collects the fragments left out because of @defer, when
+	// executing incrementally.
+	Deferred *[]*deferredFragment
 }
 
 // Given a selectionSet, adds all of the fields in that selection to
@@ -437,12 +670,16 @@ func collectFields(p collectFieldsParams) (fields map[string][]*ast.Field) {
 				!doesFragmentConditionMatch(p.ExeContext, selection, p.RuntimeType) {
 				continue
 			}
+			if p.deferFragment(selection.Directives, selection.SelectionSet) {
+				continue
+			}
 			innerParams := collectFieldsParams{
 				ExeContext:           p.ExeContext,
 				RuntimeType:          p.RuntimeType,
 				SelectionSet:         selection.SelectionSet,
 				Fields:               fields,
 				VisitedFragmentNames: p.VisitedFragmentNames,
+				Deferred:             p.Deferred,
 			}
 			collectFields(innerParams)
 		case *ast.FragmentSpread:
@@ -464,12 +701,16 @@ func collectFields(p collectFieldsParams) (fields map[string][]*ast.Field) {
 				if !doesFragmentConditionMatch(p.ExeContext, fragment, p.RuntimeType) {
 					continue
 				}
+				if p.deferFragment(selection.Directives, fragment.GetSelectionSet()) {
+					continue
+				}
 				innerParams := collectFieldsParams{
 					ExeContext:           p.ExeContext,
 					RuntimeType:          p.RuntimeType,
 					SelectionSet:         fragment.GetSelectionSet(),
 					Fields:               fields,
 					VisitedFragmentNames: p.VisitedFragmentNames,
+					Deferred:             p.Deferred,
 				}
 				collectFields(innerParams)
 			}
@@ -478,6 +719,24 @@ func collectFields(p collectFieldsParams) (fields map[string][]*ast.Field) {
 	return fields
 }
 
+// deferFragment records the given fragment in p.Deferred instead of collecting
+// its fields if it has an enabled @defer directive.
+func (p collectFieldsParams) deferFragment(directives []*ast.Directive, selectionSet *ast.SelectionSet) bool {
+	if p.Deferred == nil {
+		return false
+	}
+	label, ok := getDeferValues(p.ExeContext, directives)
+	if !ok {
+		return false
+	}
+	*p.Deferred = append(*p.Deferred, &deferredFragment{
+		label:        label,
+		runtimeType:  p.RuntimeType,
+		selectionSet: selectionSet,
+	})
+	return true
+}
+
 // Determines if a field should be included based on the @include and @skip
 // directives, where @skip has higher precedence than @include.
 func shouldIncludeNode(eCtx *executionContext, directives []*ast.Directive) bool {
@@ -586,14 +845,14 @@ func handleFieldError(r any, fieldNodes []ast.Node, path *ResponsePath, returnTy
 	if _, ok := returnType.(*NonNull); ok {
 		panic(err)
 	}
-	eCtx.Errors = append(eCtx.Errors, gqlerrors.FormatError(err))
+	eCtx.addErrors(gqlerrors.FormatError(err))
 }
 
 // Resolves the field on the given source object. In particular, this
 // figures out the value that the field returns by calling its resolve function,
 // then calls completeValue to complete promises, serialize scalars, or execute
 // the sub-selection-set for objects.
-func resolveField(eCtx *executionContext, parentType *Object, source any, fieldASTs []*ast.Field, path *ResponsePath) (result any, resultState resolveFieldResultState) {
+func resolveField(eCtx *executionContext, ctx context.Context, parentType *Object, source any, fieldASTs []*ast.Field, path *ResponsePath) (result any, resultState resolveFieldResultState) {
 	// catch panic from resolveFn
 	var returnType Output
 	defer func() (any, resolveFieldResultState) {
@@ -616,10 +875,21 @@ func resolveField(eCtx *executionContext, parentType *Object, source any, fieldA
 		return nil, resultState
 	}
 	returnType = fieldDef.Type
+
+	// stop resolving fields once nobody waits for the response anymore
+	if err := ctx.Err(); err != nil {
+		panic(err)
+	}
+
 	resolveFn := fieldDef.Resolve
 	if resolveFn == nil {
 		resolveFn = DefaultResolveFn
 	}
+	resolveFn = wrapDirectiveResolvers(eCtx, fieldDef, fieldAST, resolveFn)
+	middleware := eCtx.Schema.fieldMiddleware
+	for i := len(middleware) - 1; i >= 0; i-- {
+		resolveFn = middleware[i](resolveFn)
+	}
 
 	// Build a map of arguments from the field.arguments AST, using the
 	// variables scope to fulfill any variable references.
@@ -641,32 +911,159 @@ func resolveField(eCtx *executionContext, parentType *Object, source any, fieldA
 
 	var resolveFnError error
 
-	extErrs, resolveFieldFinishFn := handleExtensionsResolveFieldDidStart(eCtx.Schema.extensions, eCtx, &info)
+	// the fields of the value are resolved with the context of this field, so
+	// that extensions see them as nested in it
+	ctx, extErrs, resolveFieldFinishFn := handleExtensionsResolveFieldDidStart(ctx, eCtx.Schema.extensions, &info)
 	if len(extErrs) != 0 {
-		eCtx.Errors = append(eCtx.Errors, extErrs...)
+		eCtx.addErrors(extErrs...)
 	}
 
-	result, resolveFnError = resolveFn(ResolveParams{
+	result, resolveFnError = resolveWithTimeout(fieldDef.Timeout, resolveFn, ResolveParams{
 		Source:  source,
 		Args:    args,
 		Info:    info,
-		Context: eCtx.Context,
+		Context: ctx,
 	})
 
 	extErrs = resolveFieldFinishFn(result, resolveFnError)
 	if len(extErrs) != 0 {
-		eCtx.Errors = append(eCtx.Errors, extErrs...)
+		eCtx.addErrors(extErrs...)
 	}
 
 	if resolveFnError != nil {
 		panic(resolveFnError)
 	}
 
-	completed := completeValueCatchingError(eCtx, returnType, fieldASTs, info, path, result)
+	completed := completeValueCatchingError(eCtx, ctx, returnType, fieldASTs, info, path, result)
 	return completed, resultState
 }
 
-func completeValueCatchingError(eCtx *executionContext, returnType Type, fieldASTs []*ast.Field, info ResolveInfo, path *ResponsePath, result any) (completed any) {
+// FieldTimeoutError is the error of a field whose resolver didn't return
+// within the Timeout of the field.
+type FieldTimeoutError struct {
+	ParentType string
+	FieldName  string
+	Timeout    time.Duration
+}
+
+func (e *FieldTimeoutError) Error() string {
+	return fmt.Sprintf("Field %v.%v timed out after %v.", e.ParentType, e.FieldName, e.Timeout)
+}
+
+// Unwrap returns context.DeadlineExceeded.
+func (e *FieldTimeoutError) Unwrap() error {
+	return context.DeadlineExceeded
+}
+
+// resolveWithTimeout calls resolveFn, giving up once the given timeout expires
+// if any. The resolver is then left running in its own goroutine, with its
+// context canceled. When the resolver returns a thunk, its context is kept
+// alive and its deadline enforced until the thunk returns.
+func resolveWithTimeout(timeout time.Duration, resolveFn FieldResolveFn, p ResolveParams) (any, error) {
+	if timeout <= 0 {
+		return resolveFn(p)
+	}
+	parentCtx := p.Context
+	ctx, cancel := context.WithTimeout(parentCtx, timeout)
+	p.Context = ctx
+
+	result, err := callBeforeDeadline(ctx, parentCtx, timeout, p.Info, func() (any, error) {
+		return resolveFn(p)
+	})
+	thunk, ok := result.(func() (any, error))
+	if err != nil || !ok {
+		cancel()
+		return result, err
+	}
+	return func() (any, error) {
+		defer cancel()
+		return callBeforeDeadline(ctx, parentCtx, timeout, p.Info, thunk)
+	}, nil
+}
+
+// callBeforeDeadline calls fn, giving up once ctx, derived from parentCtx
+// with the given timeout, is done.
+func callBeforeDeadline(ctx, parentCtx context.Context, timeout time.Duration, info ResolveInfo, fn func() (any, error)) (any, error) {
+	type resolved struct {
+		result   any
+		err      error
+		panicked any
+	}
+	done := make(chan resolved, 1)
+	go func() {
+		var r resolved
+		defer func() {
+			// forward panics to be handled as field errors
+			r.panicked = recover()
+			done <- r
+		}()
+		r.result, r.err = fn()
+	}()
+
+	select {
+	case r := <-done:
+		if r.panicked != nil {
+			panic(r.panicked)
+		}
+		return r.result, r.err
+	case <-ctx.Done():
+		if err := parentCtx.Err(); err != nil {
+			return nil, err
+		}
+		parentType := ""
+		if info.ParentType != nil {
+			parentType = info.ParentType.Name()
+		}
+		return nil, &FieldTimeoutError{
+			ParentType: parentType,
+			FieldName:  info.FieldName,
+			Timeout:    timeout,
+		}
+	}
+}
+
+// wrapDirectiveResolvers wraps the resolver of a field with the Resolve hooks
+// of the directives applied on its definition, then of those applied in the
+// query. The first directive is the outermost wrapper, and so runs first.
+func wrapDirectiveResolvers(eCtx *executionContext, fieldDef *FieldDefinition, fieldAST *ast.Field, resolveFn FieldResolveFn) FieldResolveFn {
+	type directiveResolver struct {
+		resolve DirectiveResolveFn
+		args    map[string]any
+	}
+	resolvers := []directiveResolver{}
+	for _, applied := range fieldDef.AppliedDirectives {
+		directive := eCtx.Schema.Directive(applied.Name)
+		if directive == nil || directive.Resolve == nil {
+			continue
+		}
+		args := map[string]any{}
+		for _, arg := range directive.Args {
+			if value, ok := applied.Args[arg.Name()]; ok {
+				args[arg.Name()] = value
+			} else if !isNullish(arg.DefaultValue) {
+				args[arg.Name()] = arg.DefaultValue
+			}
+		}
+		resolvers = append(resolvers, directiveResolver{directive.Resolve, args})
+	}
+	for _, directiveAST := range fieldAST.Directives {
+		if directiveAST.Name == nil {
+			continue
+		}
+		directive := eCtx.Schema.Directive(directiveAST.Name.Value)
+		if directive == nil || directive.Resolve == nil {
+			continue
+		}
+		args := getArgumentValues(directive.Args, directiveAST.Arguments, eCtx.VariableValues)
+		resolvers = append(resolvers, directiveResolver{directive.Resolve, args})
+	}
+	for i := len(resolvers) - 1; i >= 0; i-- {
+		resolveFn = resolvers[i].resolve(resolvers[i].args, resolveFn)
+	}
+	return resolveFn
+}
+
+func completeValueCatchingError(eCtx *executionContext, ctx context.Context, returnType Type, fieldASTs []*ast.Field, info ResolveInfo, path *ResponsePath, result any) (completed any) {
 	// catch panic
 	defer func() any {
 		if r := recover(); r != nil {
@@ -677,26 +1074,26 @@ func completeValueCatchingError(eCtx *executionContext, returnType Type, fieldAS
 	}()
 
 	if returnType, ok := returnType.(*NonNull); ok {
-		completed := completeValue(eCtx, returnType, fieldASTs, info, path, result)
+		completed := completeValue(eCtx, ctx, returnType, fieldASTs, info, path, result)
 		return completed
 	}
-	completed = completeValue(eCtx, returnType, fieldASTs, info, path, result)
+	completed = completeValue(eCtx, ctx, returnType, fieldASTs, info, path, result)
 	return completed
 }
 
-func completeValue(eCtx *executionContext, returnType Type, fieldASTs []*ast.Field, info ResolveInfo, path *ResponsePath, result any) any {
+func completeValue(eCtx *executionContext, ctx context.Context, returnType Type, fieldASTs []*ast.Field, info ResolveInfo, path *ResponsePath, result any) any {
 
 	resultVal := reflect.ValueOf(result)
 	if resultVal.IsValid() && resultVal.Kind() == reflect.Func {
 		return func() any {
-			return completeThunkValueCatchingError(eCtx, returnType, fieldASTs, info, path, result)
+			return completeThunkValueCatchingError(eCtx, ctx, returnType, fieldASTs, info, path, result)
 		}
 	}
 
 	// If field type is NonNull, complete for inner type, and throw field error
 	// if result is null.
 	if returnType, ok := returnType.(*NonNull); ok {
-		completed := completeValue(eCtx, returnType.OfType, fieldASTs, info, path, result)
+		completed := completeValue(eCtx, ctx, returnType.OfType, fieldASTs, info, path, result)
 		if completed == nil {
 			err := NewLocatedErrorWithPath(
 				fmt.Sprintf("Cannot return null for non-nullable field %v.%v.", info.ParentType, info.FieldName),
@@ -715,30 +1112,31 @@ func completeValue(eCtx *executionContext, returnType Type, fieldASTs []*ast.Fie
 
 	// If field type is List, complete each item in the list with the inner type
 	if returnType, ok := returnType.(*List); ok {
-		return completeListValue(eCtx, returnType, fieldASTs, info, path, result)
+		return completeListValue(eCtx, ctx, returnType, fieldASTs, info, path, result)
 	}
 
 	// If field type is a leaf type, Scalar or Enum, serialize to a valid value,
-	// returning null if serialization is not possible.
+	// raising a field error if serialization is not possible, unless the schema
+	// is lenient.
 	if returnType, ok := returnType.(*Scalar); ok {
-		return completeLeafValue(returnType, result)
+		return completeLeafValue(eCtx, returnType, fieldASTs, path, result)
 	}
 	if returnType, ok := returnType.(*Enum); ok {
-		return completeLeafValue(returnType, result)
+		return completeLeafValue(eCtx, returnType, fieldASTs, path, result)
 	}
 
 	// If field type is an abstract type, Interface or Union, determine the
 	// runtime Object type and complete for that type.
 	if returnType, ok := returnType.(*Union); ok {
-		return completeAbstractValue(eCtx, returnType, fieldASTs, info, path, result)
+		return completeAbstractValue(eCtx, ctx, returnType, fieldASTs, info, path, result)
 	}
 	if returnType, ok := returnType.(*Interface); ok {
-		return completeAbstractValue(eCtx, returnType, fieldASTs, info, path, result)
+		return completeAbstractValue(eCtx, ctx, returnType, fieldASTs, info, path, result)
 	}
 
 	// If field type is Object, execute and complete all sub-selections.
 	if returnType, ok := returnType.(*Object); ok {
-		return completeObjectValue(eCtx, returnType, fieldASTs, info, path, result)
+		return completeObjectValue(eCtx, ctx, returnType, fieldASTs, info, path, result)
 	}
 
 	// Not reachable. All possible output types have been considered.
@@ -751,7 +1149,7 @@ func completeValue(eCtx *executionContext, returnType Type, fieldASTs []*ast.Fie
 	return nil
 }
 
-func completeThunkValueCatchingError(eCtx *executionContext, returnType Type, fieldASTs []*ast.Field, info ResolveInfo, path *ResponsePath, result any) (completed any) {
+func completeThunkValueCatchingError(eCtx *executionContext, ctx context.Context, returnType Type, fieldASTs []*ast.Field, info ResolveInfo, path *ResponsePath, result any) (completed any) {
 
 	// catch any panic invoked from the propertyFn (thunk)
 	defer func() {
@@ -773,24 +1171,24 @@ func completeThunkValueCatchingError(eCtx *executionContext, returnType Type, fi
 	result = fnResult
 
 	if returnType, ok := returnType.(*NonNull); ok {
-		completed := completeValue(eCtx, returnType, fieldASTs, info, path, result)
+		completed := completeValue(eCtx, ctx, returnType, fieldASTs, info, path, result)
 		return completed
 	}
-	completed = completeValue(eCtx, returnType, fieldASTs, info, path, result)
+	completed = completeValue(eCtx, ctx, returnType, fieldASTs, info, path, result)
 
 	return completed
 }
 
 // completeAbstractValue completes value of an Abstract type (Union / Interface) by determining the runtime type
 // of that value, then completing based on that type.
-func completeAbstractValue(eCtx *executionContext, returnType Abstract, fieldASTs []*ast.Field, info ResolveInfo, path *ResponsePath, result any) any {
+func completeAbstractValue(eCtx *executionContext, ctx context.Context, returnType Abstract, fieldASTs []*ast.Field, info ResolveInfo, path *ResponsePath, result any) any {
 
 	var runtimeType *Object
 
 	resolveTypeParams := ResolveTypeParams{
 		Value:   result,
 		Info:    info,
-		Context: eCtx.Context,
+		Context: ctx,
 	}
 	if unionReturnType, ok := returnType.(*Union); ok && unionReturnType.ResolveType != nil {
 		runtimeType = unionReturnType.ResolveType(resolveTypeParams)
@@ -803,7 +1201,7 @@ func completeAbstractValue(eCtx *executionContext, returnType Abstract, fieldAST
 	} else if interfaceReturnType, ok := returnType.(*Interface); ok && interfaceReturnType.ResolveType != nil {
 		runtimeType = interfaceReturnType.ResolveType(resolveTypeParams)
 		// verify the object matches the interface
-		if err := assertObjectImplementsInterface(&eCtx.Schema, runtimeType, interfaceReturnType); err != nil {
+		if err := assertImplementsInterface(&eCtx.Schema, runtimeType, interfaceReturnType); err != nil {
 			panic(err)
 		}
 	} else {
@@ -817,11 +1215,11 @@ func completeAbstractValue(eCtx *executionContext, returnType Abstract, fieldAST
 		panic(err)
 	}
 
-	return completeObjectValue(eCtx, runtimeType, fieldASTs, info, path, result)
+	return completeObjectValue(eCtx, ctx, runtimeType, fieldASTs, info, path, result)
 }
 
 // completeObjectValue complete an Object value by executing all sub-selections.
-func completeObjectValue(eCtx *executionContext, returnType *Object, fieldASTs []*ast.Field, info ResolveInfo, path *ResponsePath, result any) any {
+func completeObjectValue(eCtx *executionContext, ctx context.Context, returnType *Object, fieldASTs []*ast.Field, info ResolveInfo, path *ResponsePath, result any) any {
 
 	// If there is an isTypeOf predicate function, call it with the
 	// current result. If isTypeOf returns false, then raise an error rather
@@ -830,7 +1228,7 @@ func completeObjectValue(eCtx *executionContext, returnType *Object, fieldASTs [
 		p := IsTypeOfParams{
 			Value:   result,
 			Info:    info,
-			Context: eCtx.Context,
+			Context: ctx,
 		}
 		if !returnType.IsTypeOf(p) {
 			panic(gqlerrors.NewFormattedError(
@@ -842,6 +1240,7 @@ func completeObjectValue(eCtx *executionContext, returnType *Object, fieldASTs [
 	// Collect sub-fields to execute to complete this value.
 	subFieldASTs := map[string][]*ast.Field{}
 	visitedFragmentNames := map[string]bool{}
+	deferred := []*deferredFragment{}
 	for _, fieldAST := range fieldASTs {
 		if fieldAST == nil {
 			continue
@@ -854,33 +1253,70 @@ func completeObjectValue(eCtx *executionContext, returnType *Object, fieldASTs [
 				SelectionSet:         selectionSet,
 				Fields:               subFieldASTs,
 				VisitedFragmentNames: visitedFragmentNames,
+				Deferred:             &deferred,
 			}
 			subFieldASTs = collectFields(innerParams)
 		}
 	}
 	executeFieldsParams := executeFieldsParams{
 		ExecutionContext: eCtx,
+		Context:          ctx,
 		ParentType:       returnType,
 		Source:           result,
 		Fields:           subFieldASTs,
 		Path:             path,
 	}
-	return executeSubFields(executeFieldsParams)
+	completed := executeSubFields(executeFieldsParams)
+	eCtx.deferFragments(ctx, deferred, result, path)
+	return completed
+}
+
+// SerializationError is the error of a Scalar or Enum type failing to
+// serialize the value of a field. It is reported as a field error, with the
+// extensions of the original error if it implements gqlerrors.ExtendedError.
+type SerializationError struct {
+	Type Leaf
+	Err  error
+}
+
+func (e *SerializationError) Error() string {
+	return fmt.Sprintf("Cannot serialize value as %v: %v", e.Type, e.Err)
 }
 
-// completeLeafValue complete a leaf value (Scalar / Enum) by serializing to a valid value, returning nil if serialization is not possible.
-func completeLeafValue(returnType Leaf, result any) any {
+// Unwrap returns the original error.
+func (e *SerializationError) Unwrap() error {
+	return e.Err
+}
+
+// Extensions implements gqlerrors.ExtendedError.
+func (e *SerializationError) Extensions() map[string]any {
+	var extended gqlerrors.ExtendedError
+	if errors.As(e.Err, &extended) {
+		return extended.Extensions()
+	}
+	return nil
+}
+
+// completeLeafValue serializes the value of a Scalar or Enum field, failing
+// with a SerializationError unless the schema is lenient, in which case values
+// failing to be serialized are null.
+func completeLeafValue(eCtx *executionContext, returnType Leaf, fieldASTs []*ast.Field, path *ResponsePath, result any) any {
 	serializedResult, err := returnType.Serialize(result)
+	if err != nil && !eCtx.Schema.lenientSerialization {
+		panic(NewLocatedErrorWithPath(
+			&SerializationError{Type: returnType, Err: err},
+			FieldASTsToNodeASTs(fieldASTs),
+			path.AsArray(),
+		))
+	}
 	if err != nil || isNullish(serializedResult) {
-		// TODO(vito): handle err? errors were swallowed before, is there anything
-		// helpful to do here?
 		return nil
 	}
 	return serializedResult
 }
 
 // completeListValue complete a list value by completing each item in the list with the inner type
-func completeListValue(eCtx *executionContext, returnType *List, fieldASTs []*ast.Field, info ResolveInfo, path *ResponsePath, result any) any {
+func completeListValue(eCtx *executionContext, ctx context.Context, returnType *List, fieldASTs []*ast.Field, info ResolveInfo, path *ResponsePath, result any) any {
 	resultVal := reflect.ValueOf(result)
 	if resultVal.Kind() == reflect.Ptr {
 		resultVal = resultVal.Elem()
@@ -898,12 +1334,33 @@ func completeListValue(eCtx *executionContext, returnType *List, fieldASTs []*as
 		panic(gqlerrors.FormatError(err))
 	}
 
+	// @stream only applies to the list of the field itself, not to the lists
+	// nested in it, whose paths end with an index.
+	streamLabel, streamCount, stream := "", 0, false
+	if path != nil {
+		if _, ok := path.Key.(string); ok {
+			streamLabel, streamCount, stream = getStreamValues(eCtx, fieldASTs)
+		}
+	}
+
 	itemType := returnType.OfType
 	completedResults := make([]any, 0, resultVal.Len())
 	for i := 0; i < resultVal.Len(); i++ {
+		if stream && i >= streamCount {
+			items := make([]any, 0, resultVal.Len()-i)
+			for j := i; j < resultVal.Len(); j++ {
+				items = append(items, resultVal.Index(j).Interface())
+			}
+			eCtx.streamItems(ctx, streamLabel, itemType, fieldASTs, info, path, items, i)
+			break
+		}
+		// stop completing items once nobody waits for the response anymore
+		if err := ctx.Err(); err != nil {
+			panic(err)
+		}
 		val := resultVal.Index(i).Interface()
 		fieldPath := path.WithKey(i)
-		completedItem := completeValueCatchingError(eCtx, itemType, fieldASTs, info, fieldPath, val)
+		completedItem := completeValueCatchingError(eCtx, ctx, itemType, fieldASTs, info, fieldPath, val)
 		completedResults = append(completedResults, completedItem)
 	}
 	return completedResults
@@ -1053,30 +1510,38 @@ type orderedField struct {
 
 // orders fields from a fields map by location in the source
 func orderedFields(fields map[string][]*ast.Field) []*orderedField {
-	orderedFields := []*orderedField{}
-	fieldMap := map[int]*orderedField{}
-	startLocs := []int{}
+	orderedFields := make([]*orderedField, 0, len(fields))
+	startLocs := make(map[*orderedField]int, len(fields))
 
 	for responseName, fieldASTs := range fields {
 		// find the lowest location in the current fieldASTs
 		lowest := -1
 		for _, fieldAST := range fieldASTs {
+			if fieldAST.GetLoc() == nil {
+				continue
+			}
 			loc := fieldAST.GetLoc().Start
 			if lowest == -1 || loc < lowest {
 				lowest = loc
 			}
 		}
-		startLocs = append(startLocs, lowest)
-		fieldMap[lowest] = &orderedField{
+		field := &orderedField{
 			responseName: responseName,
 			fieldASTs:    fieldASTs,
 		}
+		startLocs[field] = lowest
+		orderedFields = append(orderedFields, field)
 	}
 
-	sort.Ints(startLocs)
-	for _, startLoc := range startLocs {
-		orderedFields = append(orderedFields, fieldMap[startLoc])
-	}
+	// fields without a location (e.g. from a document parsed with NoLocation)
+	// fall back to being ordered by response name
+	sort.Slice(orderedFields, func(i, j int) bool {
+		a, b := orderedFields[i], orderedFields[j]
+		if startLocs[a] != startLocs[b] {
+			return startLocs[a] < startLocs[b]
+		}
+		return a.responseName < b.responseName
+	})
 
 	return orderedFields
 }
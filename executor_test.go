@@ -6,6 +6,8 @@ import (
 	"errors"
 	"fmt"
 	"reflect"
+	"sync"
+	"sync/atomic"
 	"testing"
 	"time"
 
@@ -2427,3 +2429,450 @@ func TestQuery_OriginalErrorPanic(t *testing.T) {
 		t.Fatalf("unexpected error: %v", reflect.TypeOf(err))
 	}
 }
+
+func orderedResultsSchema(t *testing.T) graphql.Schema {
+	var objectType *graphql.Object
+	objectType = graphql.NewObject(graphql.ObjectConfig{
+		Name: "Object",
+		Fields: (graphql.FieldsThunk)(func() graphql.Fields {
+			return graphql.Fields{
+				"zebra": &graphql.Field{
+					Type: graphql.String,
+					Resolve: func(p graphql.ResolveParams) (any, error) {
+						return "z", nil
+					},
+				},
+				"apple": &graphql.Field{
+					Type: graphql.String,
+					Resolve: func(p graphql.ResolveParams) (any, error) {
+						return func() (any, error) { return "a", nil }, nil
+					},
+				},
+				"mango": &graphql.Field{
+					Type: graphql.String,
+					Resolve: func(p graphql.ResolveParams) (any, error) {
+						return "m", nil
+					},
+				},
+				"nested": &graphql.Field{
+					Type: graphql.NewList(objectType),
+					Resolve: func(p graphql.ResolveParams) (any, error) {
+						return []any{struct{}{}}, nil
+					},
+				},
+			}
+		}),
+	})
+	schema, err := graphql.NewSchema(graphql.SchemaConfig{
+		Query:    objectType,
+		Mutation: objectType,
+	})
+	if err != nil {
+		t.Fatalf("Error in schema %v", err.Error())
+	}
+	return schema
+}
+
+func TestExecutesOrderedResults(t *testing.T) {
+	schema := orderedResultsSchema(t)
+	for _, operation := range []string{"query", "mutation"} {
+		t.Run(operation, func(t *testing.T) {
+			query := operation + ` {
+				zebra
+				nested { mango ... on Object { apple } zebra }
+				apple
+				m: mango
+			}`
+			result := graphql.Do(graphql.Params{
+				Schema:         schema,
+				RequestString:  query,
+				OrderedResults: true,
+			})
+			if len(result.Errors) != 0 {
+				t.Fatalf("wrong result, unexpected errors: %v", result.Errors)
+			}
+			b, err := json.Marshal(result.Data)
+			if err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+			expected := `{"zebra":"z","nested":[{"mango":"m","apple":"a","zebra":"z"}],"apple":"a","m":"m"}`
+			if string(b) != expected {
+				t.Fatalf("wrong result, expected %s, got %s", expected, b)
+			}
+
+			data, ok := result.Data.(*graphql.OrderedMap)
+			if !ok {
+				t.Fatalf("wrong result, expected *graphql.OrderedMap, got %T", result.Data)
+			}
+			expectedData := map[string]any{
+				"zebra": "z",
+				"nested": []any{
+					map[string]any{"mango": "m", "apple": "a", "zebra": "z"},
+				},
+				"apple": "a",
+				"m":     "m",
+			}
+			if !reflect.DeepEqual(expectedData, data.Map()) {
+				t.Fatalf("Unexpected result, Diff: %v", testutil.Diff(expectedData, data.Map()))
+			}
+		})
+	}
+}
+
+func TestParallelismResolvesSiblingThunksConcurrently(t *testing.T) {
+	const siblings = 4
+	var started sync.WaitGroup
+	started.Add(siblings)
+
+	// each thunk only returns once all of its siblings have started, which
+	// would deadlock if they were called one at a time
+	blockingField := func(value string, err error) *graphql.Field {
+		return &graphql.Field{
+			Type: graphql.String,
+			Resolve: func(p graphql.ResolveParams) (any, error) {
+				return func() (any, error) {
+					started.Done()
+					started.Wait()
+					return value, err
+				}, nil
+			},
+		}
+	}
+	schema, err := graphql.NewSchema(graphql.SchemaConfig{
+		Query: graphql.NewObject(graphql.ObjectConfig{
+			Name: "Query",
+			Fields: graphql.Fields{
+				"a": blockingField("a", nil),
+				"b": blockingField("b", nil),
+				"c": blockingField("", errors.New("c failed")),
+				"d": blockingField("", errors.New("d failed")),
+			},
+		}),
+	})
+	if err != nil {
+		t.Fatalf("Error in schema %v", err.Error())
+	}
+
+	done := make(chan *graphql.Result, 1)
+	go func() {
+		done <- graphql.Do(graphql.Params{
+			Schema:        schema,
+			RequestString: `{ a b c d }`,
+			Parallelism:   siblings,
+		})
+	}()
+
+	var result *graphql.Result
+	select {
+	case result = <-done:
+	case <-time.After(5 * time.Second):
+		t.Fatal("sibling thunks were not resolved concurrently")
+	}
+
+	expectedData := map[string]any{
+		"a": "a",
+		"b": "b",
+		"c": nil,
+		"d": nil,
+	}
+	if !reflect.DeepEqual(expectedData, result.Data) {
+		t.Fatalf("Unexpected result, Diff: %v", testutil.Diff(expectedData, result.Data))
+	}
+	if len(result.Errors) != 2 {
+		t.Fatalf("wrong result, expected 2 errors, got %v", result.Errors)
+	}
+}
+
+func serializationErrorsSchema(t *testing.T, lenient bool) graphql.Schema {
+	oddType := graphql.NewScalar(graphql.ScalarConfig{
+		Name: "Odd",
+		Serialize: func(value any) (any, error) {
+			if n, ok := value.(int); ok && n%2 == 1 {
+				return n, nil
+			}
+			return nil, &extendedError{
+				error:      fmt.Errorf("%v is not odd", value),
+				extensions: map[string]any{"code": "NOT_ODD"},
+			}
+		},
+	})
+	schema, err := graphql.NewSchema(graphql.SchemaConfig{
+		Query: graphql.NewObject(graphql.ObjectConfig{
+			Name: "Query",
+			Fields: graphql.Fields{
+				"odds": &graphql.Field{
+					Type: graphql.NewList(oddType),
+					Resolve: func(p graphql.ResolveParams) (any, error) {
+						return []int{1, 2, 3}, nil
+					},
+				},
+				"nonNullOdd": &graphql.Field{
+					Type: graphql.NewNonNull(oddType),
+					Resolve: func(p graphql.ResolveParams) (any, error) {
+						return 4, nil
+					},
+				},
+			},
+		}),
+		LenientSerialization: lenient,
+	})
+	if err != nil {
+		t.Fatalf("Error in schema %v", err.Error())
+	}
+	return schema
+}
+
+func TestSerializationErrorsAreFieldErrors(t *testing.T) {
+	result := graphql.Do(graphql.Params{
+		Schema:        serializationErrorsSchema(t, false),
+		RequestString: `{ odds }`,
+	})
+	expected := &graphql.Result{
+		Data: map[string]any{
+			"odds": []any{1, nil, 3},
+		},
+		Errors: []gqlerrors.FormattedError{
+			{
+				Message:    "Cannot serialize value as Odd: 2 is not odd",
+				Locations:  []location.SourceLocation{{Line: 1, Column: 3}},
+				Path:       []any{"odds", 1},
+				Extensions: map[string]any{"code": "NOT_ODD"},
+			},
+		},
+	}
+	if !testutil.EqualResults(expected, result) {
+		t.Fatalf("Unexpected result, Diff: %v", testutil.Diff(expected, result))
+	}
+
+	var serializationErr *graphql.SerializationError
+	if !errors.As(result.Errors[0].OriginalError(), &serializationErr) {
+		t.Fatalf("expected a SerializationError, got: %#v", result.Errors[0].OriginalError())
+	}
+	var extended *extendedError
+	if !errors.As(serializationErr, &extended) {
+		t.Fatalf("expected the SerializationError to wrap the original error")
+	}
+}
+
+func TestSerializationErrorsOfNonNullFields(t *testing.T) {
+	result := graphql.Do(graphql.Params{
+		Schema:        serializationErrorsSchema(t, false),
+		RequestString: `{ nonNullOdd }`,
+	})
+	expected := &graphql.Result{
+		Data: nil,
+		Errors: []gqlerrors.FormattedError{
+			{
+				Message:    "Cannot serialize value as Odd: 4 is not odd",
+				Locations:  []location.SourceLocation{{Line: 1, Column: 3}},
+				Path:       []any{"nonNullOdd"},
+				Extensions: map[string]any{"code": "NOT_ODD"},
+			},
+		},
+	}
+	if !testutil.EqualResults(expected, result) {
+		t.Fatalf("Unexpected result, Diff: %v", testutil.Diff(expected, result))
+	}
+}
+
+func TestLenientSerializationReturnsNull(t *testing.T) {
+	result := graphql.Do(graphql.Params{
+		Schema:        serializationErrorsSchema(t, true),
+		RequestString: `{ odds }`,
+	})
+	expected := &graphql.Result{
+		Data: map[string]any{
+			"odds": []any{1, nil, 3},
+		},
+	}
+	if !reflect.DeepEqual(expected, result) {
+		t.Fatalf("Unexpected result, Diff: %v", testutil.Diff(expected, result))
+	}
+}
+
+func TestFieldTimeout(t *testing.T) {
+	canceled := make(chan struct{})
+	schema, err := graphql.NewSchema(graphql.SchemaConfig{
+		Query: graphql.NewObject(graphql.ObjectConfig{
+			Name: "Query",
+			Fields: graphql.Fields{
+				"fast": &graphql.Field{
+					Type:    graphql.String,
+					Timeout: time.Second,
+					Resolve: func(p graphql.ResolveParams) (any, error) {
+						return "fast", nil
+					},
+				},
+				"slow": &graphql.Field{
+					Type:    graphql.String,
+					Timeout: 20 * time.Millisecond,
+					Resolve: func(p graphql.ResolveParams) (any, error) {
+						select {
+						case <-p.Context.Done():
+							close(canceled)
+							return nil, p.Context.Err()
+						case <-time.After(5 * time.Second):
+							return "slow", nil
+						}
+					},
+				},
+			},
+		}),
+	})
+	if err != nil {
+		t.Fatalf("Error in schema %v", err.Error())
+	}
+
+	result := graphql.Do(graphql.Params{
+		Schema:        schema,
+		RequestString: `{ fast slow }`,
+	})
+	expected := &graphql.Result{
+		Data: map[string]any{
+			"fast": "fast",
+			"slow": nil,
+		},
+		Errors: []gqlerrors.FormattedError{
+			{
+				Message:   "Field Query.slow timed out after 20ms.",
+				Locations: []location.SourceLocation{{Line: 1, Column: 8}},
+				Path:      []any{"slow"},
+			},
+		},
+	}
+	if !testutil.EqualResults(expected, result) {
+		t.Fatalf("Unexpected result, Diff: %v", testutil.Diff(expected, result))
+	}
+	if !errors.Is(result.Errors[0].OriginalError(), context.DeadlineExceeded) {
+		t.Fatalf("expected the error to be a deadline exceeded error, got: %#v", result.Errors[0].OriginalError())
+	}
+	select {
+	case <-canceled:
+	case <-time.After(time.Second):
+		t.Fatal("expected the context of the resolver to be canceled")
+	}
+}
+
+func TestFieldTimeoutAppliesToThunks(t *testing.T) {
+	schema, err := graphql.NewSchema(graphql.SchemaConfig{
+		Query: graphql.NewObject(graphql.ObjectConfig{
+			Name: "Query",
+			Fields: graphql.Fields{
+				"fastThunk": &graphql.Field{
+					Type:    graphql.String,
+					Timeout: time.Second,
+					Resolve: func(p graphql.ResolveParams) (any, error) {
+						return func() (any, error) {
+							if err := p.Context.Err(); err != nil {
+								return nil, err
+							}
+							return "fast", nil
+						}, nil
+					},
+				},
+				"slowThunk": &graphql.Field{
+					Type:    graphql.String,
+					Timeout: 20 * time.Millisecond,
+					Resolve: func(p graphql.ResolveParams) (any, error) {
+						return func() (any, error) {
+							time.Sleep(5 * time.Second)
+							return "slow", nil
+						}, nil
+					},
+				},
+			},
+		}),
+	})
+	if err != nil {
+		t.Fatalf("Error in schema %v", err.Error())
+	}
+
+	result := graphql.Do(graphql.Params{
+		Schema:        schema,
+		RequestString: `{ fastThunk slowThunk }`,
+	})
+	expected := &graphql.Result{
+		Data: map[string]any{
+			"fastThunk": "fast",
+			"slowThunk": nil,
+		},
+		Errors: []gqlerrors.FormattedError{
+			{
+				Message:   "Field Query.slowThunk timed out after 20ms.",
+				Locations: []location.SourceLocation{{Line: 1, Column: 13}},
+				Path:      []any{"slowThunk"},
+			},
+		},
+	}
+	if !testutil.EqualResults(expected, result) {
+		t.Fatalf("Unexpected result, Diff: %v", testutil.Diff(expected, result))
+	}
+}
+
+// canceledOnSignalContext is canceled once signaled, which only shows through
+// Err: its Done channel is never closed so that Execute waits for the
+// execution to stop instead of returning as soon as it is canceled.
+type canceledOnSignalContext struct {
+	context.Context
+	canceled chan struct{}
+}
+
+func (ctx *canceledOnSignalContext) Err() error {
+	select {
+	case <-ctx.canceled:
+		return context.Canceled
+	default:
+		return nil
+	}
+}
+
+func TestContextCancellationStopsResolvingListItems(t *testing.T) {
+	var resolved int32
+	ctx := &canceledOnSignalContext{
+		Context:  context.Background(),
+		canceled: make(chan struct{}),
+	}
+
+	itemType := graphql.NewObject(graphql.ObjectConfig{
+		Name: "Item",
+		Fields: graphql.Fields{
+			"n": &graphql.Field{
+				Type: graphql.Int,
+				Resolve: func(p graphql.ResolveParams) (any, error) {
+					if atomic.AddInt32(&resolved, 1) == 1 {
+						close(ctx.canceled)
+					}
+					return p.Source, nil
+				},
+			},
+		},
+	})
+	schema, err := graphql.NewSchema(graphql.SchemaConfig{
+		Query: graphql.NewObject(graphql.ObjectConfig{
+			Name: "Query",
+			Fields: graphql.Fields{
+				"items": &graphql.Field{
+					Type: graphql.NewList(itemType),
+					Resolve: func(p graphql.ResolveParams) (any, error) {
+						return []int{1, 2, 3, 4, 5}, nil
+					},
+				},
+			},
+		}),
+	})
+	if err != nil {
+		t.Fatalf("Error in schema %v", err.Error())
+	}
+
+	result := graphql.Do(graphql.Params{
+		Schema:        schema,
+		RequestString: `{ items { n } }`,
+		Context:       ctx,
+	})
+	if len(result.Errors) == 0 || !errors.Is(result.Errors[0].OriginalError(), context.Canceled) {
+		t.Fatalf("expected a cancellation error, got: %v", result)
+	}
+	if n := atomic.LoadInt32(&resolved); n != 1 {
+		t.Fatalf("expected the items after the cancellation not to be resolved, got %d resolved", n)
+	}
+}
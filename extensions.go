@@ -5,6 +5,7 @@ import (
 	"fmt"
 
 	"github.com/dagger/graphql/gqlerrors"
+	"github.com/dagger/graphql/language/ast"
 )
 
 type (
@@ -27,27 +28,68 @@ type (
 	ResolveFieldFinishFunc func(any, error)
 	// resolveFieldFinishFuncHandler calls the resolveFieldFinishFns for all the extensions
 	resolveFieldFinishFuncHandler func(any, error) []gqlerrors.FormattedError
+
+	// SubscriptionFinishFunc is called when a subscription ends, with the error
+	// that ended it or nil if its source stream was exhausted
+	SubscriptionFinishFunc func(error)
+	// subscriptionFinishFuncHandler calls the SubscriptionFinishFuncs of the extensions
+	subscriptionFinishFuncHandler func(error) []gqlerrors.FormattedError
 )
 
-// Extension is an interface for extensions in graphql
+// Extension is an interface for extensions in graphql. An extension takes part
+// in the lifecycle of requests by implementing any of InitExtension,
+// ParseExtension, ValidationExtension, ExecutionExtension, FieldExtension and
+// ResultExtension, or FullExtension to implement them all.
 type Extension interface {
+	// Name returns the name of the extension (make sure it's custom)
+	Name() string
+}
+
+// InitExtension is an Extension initialized for each request
+type InitExtension interface {
+	Extension
+
 	// Init is used to help you initialize the extension
 	Init(context.Context, *Params) context.Context
+}
 
-	// Name returns the name of the extension (make sure it's custom)
-	Name() string
+// ParseExtension is an Extension notified about the parse of requests
+type ParseExtension interface {
+	Extension
 
 	// ParseDidStart is being called before starting the parse
 	ParseDidStart(context.Context) (context.Context, ParseFinishFunc)
+}
+
+// ValidationExtension is an Extension notified about the validation of requests
+type ValidationExtension interface {
+	Extension
 
 	// ValidationDidStart is called just before the validation begins
 	ValidationDidStart(context.Context) (context.Context, ValidationFinishFunc)
+}
+
+// ExecutionExtension is an Extension notified about the execution of requests
+type ExecutionExtension interface {
+	Extension
 
 	// ExecutionDidStart notifies about the start of the execution
 	ExecutionDidStart(context.Context) (context.Context, ExecutionFinishFunc)
+}
+
+// FieldExtension is an Extension notified about the resolving of fields
+type FieldExtension interface {
+	Extension
 
-	// ResolveFieldDidStart notifies about the start of the resolving of a field
+	// ResolveFieldDidStart notifies about the start of the resolving of a field.
+	// The returned context is the one the field is resolved with, and the one
+	// the fields of its value start from.
 	ResolveFieldDidStart(context.Context, *ResolveInfo) (context.Context, ResolveFieldFinishFunc)
+}
+
+// ResultExtension is an Extension adding data to the extensions of results
+type ResultExtension interface {
+	Extension
 
 	// HasResult returns if the extension wants to add data to the result
 	HasResult() bool
@@ -56,10 +98,67 @@ type Extension interface {
 	GetResult(context.Context) any
 }
 
+// FullExtension is an Extension taking part in the whole lifecycle of requests
+type FullExtension interface {
+	InitExtension
+	ParseExtension
+	ValidationExtension
+	ExecutionExtension
+	FieldExtension
+	ResultExtension
+}
+
+// SubscriptionExtension can be implemented by an Extension to be notified about
+// the lifetime of subscriptions. The other hooks of the Extension are called as
+// for queries, with ExecutionDidStart being called once per event.
+type SubscriptionExtension interface {
+	Extension
+
+	// SubscriptionDidStart is called before the source stream of a subscription
+	// is created, the returned context is used for the whole subscription
+	SubscriptionDidStart(context.Context) (context.Context, SubscriptionFinishFunc)
+}
+
+// DocumentExtension can be implemented by an Extension to rewrite the document
+// of a request once parsed, e.g. to strip fields or to add __typename fields.
+type DocumentExtension interface {
+	Extension
+
+	// RewriteDocument returns the document to validate and execute instead of
+	// the given one. The given document may be cached and so must not be
+	// modified, a modified copy is to be returned instead.
+	RewriteDocument(context.Context, *ast.Document) (*ast.Document, error)
+}
+
+// VariablesExtension can be implemented by an Extension to rewrite the
+// variables of a request before its execution.
+type VariablesExtension interface {
+	Extension
+
+	// RewriteVariableValues returns the variables to execute the request with
+	// instead of the given ones.
+	RewriteVariableValues(context.Context, map[string]any) (map[string]any, error)
+}
+
+// CachedResultExtension can be implemented by an Extension to skip the
+// execution of a request, e.g. to serve responses from a cache.
+type CachedResultExtension interface {
+	Extension
+
+	// CachedResult is called once the request is validated, and returns the
+	// result to respond with and true to skip its execution. The execution
+	// hooks of the extensions aren't called for a cached result.
+	CachedResult(context.Context, *Params) (*Result, bool)
+}
+
 // handleExtensionsInits handles all the init functions for all the extensions in the schema
 func handleExtensionsInits(p *Params) gqlerrors.FormattedErrors {
 	errs := gqlerrors.FormattedErrors{}
 	for _, ext := range p.Schema.extensions {
+		initExt, ok := ext.(InitExtension)
+		if !ok {
+			continue
+		}
 		func() {
 			// catch panic from an extension init fn
 			defer func() {
@@ -68,7 +167,7 @@ func handleExtensionsInits(p *Params) gqlerrors.FormattedErrors {
 				}
 			}()
 			// update context
-			p.Context = ext.Init(p.Context, p)
+			p.Context = initExt.Init(p.Context, p)
 		}()
 	}
 	return errs
@@ -76,9 +175,16 @@ func handleExtensionsInits(p *Params) gqlerrors.FormattedErrors {
 
 // handleExtensionsParseDidStart runs the ParseDidStart functions for each extension
 func handleExtensionsParseDidStart(p *Params) ([]gqlerrors.FormattedError, parseFinishFuncHandler) {
-	fs := map[string]ParseFinishFunc{}
+	var (
+		names []string
+		fs    []ParseFinishFunc
+	)
 	errs := gqlerrors.FormattedErrors{}
 	for _, ext := range p.Schema.extensions {
+		parseExt, ok := ext.(ParseExtension)
+		if !ok {
+			continue
+		}
 		var (
 			ctx      context.Context
 			finishFn ParseFinishFunc
@@ -90,15 +196,18 @@ func handleExtensionsParseDidStart(p *Params) ([]gqlerrors.FormattedError, parse
 					errs = append(errs, gqlerrors.FormatError(fmt.Errorf("%s.ParseDidStart: %v", ext.Name(), r.(error))))
 				}
 			}()
-			ctx, finishFn = ext.ParseDidStart(p.Context)
+			ctx, finishFn = parseExt.ParseDidStart(p.Context)
 			// update context
 			p.Context = ctx
-			fs[ext.Name()] = finishFn
+			names = append(names, ext.Name())
+			fs = append(fs, finishFn)
 		}()
 	}
 	return errs, func(err error) []gqlerrors.FormattedError {
 		errs := gqlerrors.FormattedErrors{}
-		for name, fn := range fs {
+		// finish functions run in the reverse order of the extensions
+		for i := len(fs) - 1; i >= 0; i-- {
+			name, fn := names[i], fs[i]
 			func() {
 				// catch panic from a finishFn
 				defer func() {
@@ -113,11 +222,101 @@ func handleExtensionsParseDidStart(p *Params) ([]gqlerrors.FormattedError, parse
 	}
 }
 
+// handleExtensionsRewriteDocument lets the extensions rewrite the parsed document, each
+// extension being given the document returned by the previous one
+func handleExtensionsRewriteDocument(p *Params, doc *ast.Document) (*ast.Document, []gqlerrors.FormattedError) {
+	for _, ext := range p.Schema.extensions {
+		docExt, ok := ext.(DocumentExtension)
+		if !ok {
+			continue
+		}
+		var err error
+		// catch panic from an extension's rewriteDocument function
+		func() {
+			defer func() {
+				if r := recover(); r != nil {
+					err = fmt.Errorf("%s.RewriteDocument: %v", ext.Name(), r.(error))
+				}
+			}()
+			doc, err = docExt.RewriteDocument(p.Context, doc)
+		}()
+		if err != nil {
+			return nil, gqlerrors.FormatErrors(err)
+		}
+	}
+	return doc, nil
+}
+
+// handleExtensionsRewriteVariableValues lets the extensions rewrite the variables of the request
+func handleExtensionsRewriteVariableValues(p *Params) []gqlerrors.FormattedError {
+	for _, ext := range p.Schema.extensions {
+		varsExt, ok := ext.(VariablesExtension)
+		if !ok {
+			continue
+		}
+		var err error
+		// catch panic from an extension's rewriteVariableValues function
+		func() {
+			defer func() {
+				if r := recover(); r != nil {
+					err = fmt.Errorf("%s.RewriteVariableValues: %v", ext.Name(), r.(error))
+				}
+			}()
+			var vars map[string]any
+			vars, err = varsExt.RewriteVariableValues(p.Context, p.VariableValues)
+			if err == nil {
+				p.VariableValues = vars
+			}
+		}()
+		if err != nil {
+			return gqlerrors.FormatErrors(err)
+		}
+	}
+	return nil
+}
+
+// handleExtensionsCachedResult returns the first result cached by an extension, if any
+func handleExtensionsCachedResult(p *Params) (*Result, bool) {
+	for _, ext := range p.Schema.extensions {
+		cacheExt, ok := ext.(CachedResultExtension)
+		if !ok {
+			continue
+		}
+		var (
+			result *Result
+			cached bool
+		)
+		// catch panic from an extension's cachedResult function
+		func() {
+			defer func() {
+				if r := recover(); r != nil {
+					result = &Result{
+						Errors: gqlerrors.FormatErrors(fmt.Errorf("%s.CachedResult: %v", ext.Name(), r.(error))),
+					}
+					cached = true
+				}
+			}()
+			result, cached = cacheExt.CachedResult(p.Context, p)
+		}()
+		if cached {
+			return result, true
+		}
+	}
+	return nil, false
+}
+
 // handleExtensionsValidationDidStart notifies the extensions about the start of the validation process
 func handleExtensionsValidationDidStart(p *Params) ([]gqlerrors.FormattedError, validationFinishFuncHandler) {
-	fs := map[string]ValidationFinishFunc{}
+	var (
+		names []string
+		fs    []ValidationFinishFunc
+	)
 	errs := gqlerrors.FormattedErrors{}
 	for _, ext := range p.Schema.extensions {
+		validationExt, ok := ext.(ValidationExtension)
+		if !ok {
+			continue
+		}
 		var (
 			ctx      context.Context
 			finishFn ValidationFinishFunc
@@ -129,15 +328,17 @@ func handleExtensionsValidationDidStart(p *Params) ([]gqlerrors.FormattedError,
 					errs = append(errs, gqlerrors.FormatError(fmt.Errorf("%s.ValidationDidStart: %v", ext.Name(), r.(error))))
 				}
 			}()
-			ctx, finishFn = ext.ValidationDidStart(p.Context)
+			ctx, finishFn = validationExt.ValidationDidStart(p.Context)
 			// update context
 			p.Context = ctx
-			fs[ext.Name()] = finishFn
+			names = append(names, ext.Name())
+			fs = append(fs, finishFn)
 		}()
 	}
 	return errs, func(errs []gqlerrors.FormattedError) []gqlerrors.FormattedError {
 		extErrs := gqlerrors.FormattedErrors{}
-		for name, finishFn := range fs {
+		for i := len(fs) - 1; i >= 0; i-- {
+			name, finishFn := names[i], fs[i]
 			func() {
 				// catch panic from a finishFn
 				defer func() {
@@ -154,9 +355,16 @@ func handleExtensionsValidationDidStart(p *Params) ([]gqlerrors.FormattedError,
 
 // handleExecutionDidStart handles the ExecutionDidStart functions
 func handleExtensionsExecutionDidStart(p *ExecuteParams) ([]gqlerrors.FormattedError, executionFinishFuncHandler) {
-	fs := map[string]ExecutionFinishFunc{}
+	var (
+		names []string
+		fs    []ExecutionFinishFunc
+	)
 	errs := gqlerrors.FormattedErrors{}
 	for _, ext := range p.Schema.extensions {
+		executionExt, ok := ext.(ExecutionExtension)
+		if !ok {
+			continue
+		}
 		var (
 			ctx      context.Context
 			finishFn ExecutionFinishFunc
@@ -168,15 +376,17 @@ func handleExtensionsExecutionDidStart(p *ExecuteParams) ([]gqlerrors.FormattedE
 					errs = append(errs, gqlerrors.FormatError(fmt.Errorf("%s.ExecutionDidStart: %v", ext.Name(), r.(error))))
 				}
 			}()
-			ctx, finishFn = ext.ExecutionDidStart(p.Context)
+			ctx, finishFn = executionExt.ExecutionDidStart(p.Context)
 			// update context
 			p.Context = ctx
-			fs[ext.Name()] = finishFn
+			names = append(names, ext.Name())
+			fs = append(fs, finishFn)
 		}()
 	}
 	return errs, func(result *Result) []gqlerrors.FormattedError {
 		extErrs := gqlerrors.FormattedErrors{}
-		for name, finishFn := range fs {
+		for i := len(fs) - 1; i >= 0; i-- {
+			name, finishFn := names[i], fs[i]
 			func() {
 				// catch panic from a finishFn
 				defer func() {
@@ -191,13 +401,69 @@ func handleExtensionsExecutionDidStart(p *ExecuteParams) ([]gqlerrors.FormattedE
 	}
 }
 
-// handleResolveFieldDidStart handles the notification of the extensions about the start of a resolve function
-func handleExtensionsResolveFieldDidStart(exts []Extension, p *executionContext, i *ResolveInfo) ([]gqlerrors.FormattedError, resolveFieldFinishFuncHandler) {
-	fs := map[string]ResolveFieldFinishFunc{}
+// handleExtensionsSubscriptionDidStart notifies the extensions about the start of a subscription
+func handleExtensionsSubscriptionDidStart(p *ExecuteParams) ([]gqlerrors.FormattedError, subscriptionFinishFuncHandler) {
+	var (
+		names []string
+		fs    []SubscriptionFinishFunc
+	)
 	errs := gqlerrors.FormattedErrors{}
 	for _, ext := range p.Schema.extensions {
+		subExt, ok := ext.(SubscriptionExtension)
+		if !ok {
+			continue
+		}
 		var (
 			ctx      context.Context
+			finishFn SubscriptionFinishFunc
+		)
+		// catch panic from an extension's subscriptionDidStart function
+		func() {
+			defer func() {
+				if r := recover(); r != nil {
+					errs = append(errs, gqlerrors.FormatError(fmt.Errorf("%s.SubscriptionDidStart: %v", ext.Name(), r.(error))))
+				}
+			}()
+			ctx, finishFn = subExt.SubscriptionDidStart(p.Context)
+			// update context
+			p.Context = ctx
+			names = append(names, ext.Name())
+			fs = append(fs, finishFn)
+		}()
+	}
+	return errs, func(err error) []gqlerrors.FormattedError {
+		extErrs := gqlerrors.FormattedErrors{}
+		for i := len(fs) - 1; i >= 0; i-- {
+			name, finishFn := names[i], fs[i]
+			func() {
+				// catch panic from a finishFn
+				defer func() {
+					if r := recover(); r != nil {
+						extErrs = append(extErrs, gqlerrors.FormatError(fmt.Errorf("%s.SubscriptionFinishFunc: %v", name, r.(error))))
+					}
+				}()
+				finishFn(err)
+			}()
+		}
+		return extErrs
+	}
+}
+
+// handleResolveFieldDidStart handles the notification of the extensions about the start of a resolve function.
+// The returned context is the one the field should be resolved with.
+func handleExtensionsResolveFieldDidStart(ctx context.Context, exts []Extension, i *ResolveInfo) (context.Context, []gqlerrors.FormattedError, resolveFieldFinishFuncHandler) {
+	var (
+		names []string
+		fs    []ResolveFieldFinishFunc
+	)
+	errs := gqlerrors.FormattedErrors{}
+	for _, ext := range exts {
+		fieldExt, ok := ext.(FieldExtension)
+		if !ok {
+			continue
+		}
+		var (
+			extCtx   context.Context
 			finishFn ResolveFieldFinishFunc
 		)
 		// catch panic from an extension's resolveFieldDidStart function
@@ -207,15 +473,17 @@ func handleExtensionsResolveFieldDidStart(exts []Extension, p *executionContext,
 					errs = append(errs, gqlerrors.FormatError(fmt.Errorf("%s.ResolveFieldDidStart: %v", ext.Name(), r.(error))))
 				}
 			}()
-			ctx, finishFn = ext.ResolveFieldDidStart(p.Context, i)
+			extCtx, finishFn = fieldExt.ResolveFieldDidStart(ctx, i)
 			// update context
-			p.Context = ctx
-			fs[ext.Name()] = finishFn
+			ctx = extCtx
+			names = append(names, ext.Name())
+			fs = append(fs, finishFn)
 		}()
 	}
-	return errs, func(val any, err error) []gqlerrors.FormattedError {
+	return ctx, errs, func(val any, err error) []gqlerrors.FormattedError {
 		extErrs := gqlerrors.FormattedErrors{}
-		for name, finishFn := range fs {
+		for i := len(fs) - 1; i >= 0; i-- {
+			name, finishFn := names[i], fs[i]
 			func() {
 				// catch panic from a finishFn
 				defer func() {
@@ -233,17 +501,21 @@ func handleExtensionsResolveFieldDidStart(exts []Extension, p *executionContext,
 func addExtensionResults(p *ExecuteParams, result *Result) {
 	if len(p.Schema.extensions) != 0 {
 		for _, ext := range p.Schema.extensions {
+			resultExt, ok := ext.(ResultExtension)
+			if !ok {
+				continue
+			}
 			func() {
 				defer func() {
 					if r := recover(); r != nil {
 						result.Errors = append(result.Errors, gqlerrors.FormatError(fmt.Errorf("%s.GetResult: %v", ext.Name(), r.(error))))
 					}
 				}()
-				if ext.HasResult() {
+				if resultExt.HasResult() {
 					if result.Extensions == nil {
 						result.Extensions = make(map[string]any)
 					}
-					result.Extensions[ext.Name()] = ext.GetResult(p.Context)
+					result.Extensions[ext.Name()] = resultExt.GetResult(p.Context)
 				}
 			}()
 		}
@@ -0,0 +1,203 @@
+package graphql_test
+
+import (
+	"context"
+	"errors"
+	"reflect"
+	"sync"
+	"testing"
+
+	"github.com/dagger/graphql"
+	"github.com/dagger/graphql/gqlerrors"
+	"github.com/dagger/graphql/language/ast"
+	"github.com/dagger/graphql/testutil"
+)
+
+// stripFields returns a copy of doc without the given top level fields
+func stripFields(doc *ast.Document, names ...string) *ast.Document {
+	stripped := *doc
+	stripped.Definitions = []ast.Node{}
+	for _, def := range doc.Definitions {
+		op, ok := def.(*ast.OperationDefinition)
+		if !ok {
+			stripped.Definitions = append(stripped.Definitions, def)
+			continue
+		}
+		opCopy := *op
+		selectionSet := *op.SelectionSet
+		selectionSet.Selections = []ast.Selection{}
+	selections:
+		for _, selection := range op.SelectionSet.Selections {
+			if field, ok := selection.(*ast.Field); ok {
+				for _, name := range names {
+					if field.Name.Value == name {
+						continue selections
+					}
+				}
+			}
+			selectionSet.Selections = append(selectionSet.Selections, selection)
+		}
+		opCopy.SelectionSet = &selectionSet
+		stripped.Definitions = append(stripped.Definitions, &opCopy)
+	}
+	return &stripped
+}
+
+func TestExtensionRewriteDocument(t *testing.T) {
+	ext := &testRewriteExt{testExt: newtestExt("testExt")}
+	ext.rewriteDocumentFn = func(ctx context.Context, doc *ast.Document) (*ast.Document, error) {
+		return stripFields(doc, "erred"), nil
+	}
+
+	schema := tinit(t)
+	schema.AddExtensions(ext)
+	cache := graphql.NewLRUDocumentCache(10)
+
+	for i := 0; i < 2; i++ {
+		result := graphql.Do(graphql.Params{
+			Schema:        schema,
+			RequestString: `query Example { a erred }`,
+			DocumentCache: cache,
+		})
+		expected := &graphql.Result{
+			Data: map[string]any{"a": "foo"},
+		}
+		if !reflect.DeepEqual(expected, result) {
+			t.Fatalf("Unexpected result, Diff: %v", testutil.Diff(expected, result))
+		}
+	}
+	if cache.Len() != 0 {
+		t.Fatalf("expected rewritten documents not to be cached")
+	}
+}
+
+func TestExtensionRewriteDocumentError(t *testing.T) {
+	ext := &testRewriteExt{testExt: newtestExt("testExt")}
+	ext.rewriteDocumentFn = func(ctx context.Context, doc *ast.Document) (*ast.Document, error) {
+		return nil, errors.New("rewrite error")
+	}
+
+	schema := tinit(t)
+	schema.AddExtensions(ext)
+
+	result := graphql.Do(graphql.Params{
+		Schema:        schema,
+		RequestString: `query Example { a }`,
+	})
+	expected := &graphql.Result{
+		Errors: gqlerrors.FormatErrors(errors.New("rewrite error")),
+	}
+	if !reflect.DeepEqual(expected, result) {
+		t.Fatalf("Unexpected result, Diff: %v", testutil.Diff(expected, result))
+	}
+}
+
+func TestExtensionRewriteVariableValues(t *testing.T) {
+	ext := &testRewriteExt{testExt: newtestExt("testExt")}
+	ext.rewriteVariableValuesFn = func(ctx context.Context, vars map[string]any) (map[string]any, error) {
+		rewritten := map[string]any{}
+		for name, value := range vars {
+			rewritten[name] = value
+		}
+		rewritten["message"] = "rewritten " + vars["message"].(string)
+		return rewritten, nil
+	}
+
+	schema := fieldMiddlewareSchema(t)
+	schema.AddExtensions(ext)
+
+	vars := map[string]any{"message": "hello"}
+	result := graphql.Do(graphql.Params{
+		Schema:         schema,
+		RequestString:  `query ($message: String) { echo(message: $message) }`,
+		VariableValues: vars,
+	})
+	expected := &graphql.Result{
+		Data: map[string]any{"echo": "rewritten hello"},
+	}
+	if !reflect.DeepEqual(expected, result) {
+		t.Fatalf("Unexpected result, Diff: %v", testutil.Diff(expected, result))
+	}
+	if vars["message"] != "hello" {
+		t.Fatalf("expected the variables of the request to be left untouched, got: %v", vars)
+	}
+}
+
+func TestExtensionCachedResult(t *testing.T) {
+	type cacheKey struct{}
+	var (
+		mu      sync.Mutex
+		results = map[string]*graphql.Result{}
+	)
+	ext := &testRewriteExt{testExt: newtestExt("testExt")}
+	ext.initFn = func(ctx context.Context, p *graphql.Params) context.Context {
+		return context.WithValue(ctx, cacheKey{}, p.RequestString)
+	}
+	ext.executionDidStartFn = func(ctx context.Context) (context.Context, graphql.ExecutionFinishFunc) {
+		return ctx, func(r *graphql.Result) {
+			mu.Lock()
+			defer mu.Unlock()
+			results[ctx.Value(cacheKey{}).(string)] = r
+		}
+	}
+	ext.cachedResultFn = func(ctx context.Context, p *graphql.Params) (*graphql.Result, bool) {
+		mu.Lock()
+		defer mu.Unlock()
+		result, ok := results[ctx.Value(cacheKey{}).(string)]
+		return result, ok
+	}
+
+	calls := 0
+	schema := fieldMiddlewareSchema(t, func(next graphql.FieldResolveFn) graphql.FieldResolveFn {
+		return func(p graphql.ResolveParams) (any, error) {
+			calls++
+			return next(p)
+		}
+	})
+	schema.AddExtensions(ext)
+
+	for i := 0; i < 3; i++ {
+		result := graphql.Do(graphql.Params{
+			Schema:        schema,
+			RequestString: `{ secret }`,
+			Context:       context.Background(),
+		})
+		expected := &graphql.Result{
+			Data: map[string]any{"secret": "s3cr3t"},
+		}
+		if !reflect.DeepEqual(expected, result) {
+			t.Fatalf("Unexpected result, Diff: %v", testutil.Diff(expected, result))
+		}
+	}
+	if calls != 1 {
+		t.Fatalf("expected a single execution, got %d", calls)
+	}
+}
+
+type testRewriteExt struct {
+	*testExt
+	rewriteDocumentFn       func(ctx context.Context, doc *ast.Document) (*ast.Document, error)
+	rewriteVariableValuesFn func(ctx context.Context, vars map[string]any) (map[string]any, error)
+	cachedResultFn          func(ctx context.Context, p *graphql.Params) (*graphql.Result, bool)
+}
+
+func (t *testRewriteExt) RewriteDocument(ctx context.Context, doc *ast.Document) (*ast.Document, error) {
+	if t.rewriteDocumentFn == nil {
+		return doc, nil
+	}
+	return t.rewriteDocumentFn(ctx, doc)
+}
+
+func (t *testRewriteExt) RewriteVariableValues(ctx context.Context, vars map[string]any) (map[string]any, error) {
+	if t.rewriteVariableValuesFn == nil {
+		return vars, nil
+	}
+	return t.rewriteVariableValuesFn(ctx, vars)
+}
+
+func (t *testRewriteExt) CachedResult(ctx context.Context, p *graphql.Params) (*graphql.Result, bool) {
+	if t.cachedResultFn == nil {
+		return nil, false
+	}
+	return t.cachedResultFn(ctx, p)
+}
@@ -5,6 +5,7 @@ import (
 	"errors"
 	"fmt"
 	"reflect"
+	"sync"
 	"testing"
 
 	"github.com/dagger/graphql"
@@ -377,6 +378,173 @@ func TestExtensionGetResultPanic(t *testing.T) {
 	}
 }
 
+func TestExtensionOrder(t *testing.T) {
+	var (
+		mu    sync.Mutex
+		trace []string
+	)
+	record := func(event string) {
+		mu.Lock()
+		defer mu.Unlock()
+		trace = append(trace, event)
+	}
+	tracing := func(name string) *testExt {
+		ext := newtestExt(name)
+		ext.parseDidStartFn = func(ctx context.Context) (context.Context, graphql.ParseFinishFunc) {
+			record(name + " parse started")
+			return ctx, func(err error) {
+				record(name + " parse finished")
+			}
+		}
+		ext.validationDidStartFn = func(ctx context.Context) (context.Context, graphql.ValidationFinishFunc) {
+			record(name + " validation started")
+			return ctx, func([]gqlerrors.FormattedError) {
+				record(name + " validation finished")
+			}
+		}
+		ext.executionDidStartFn = func(ctx context.Context) (context.Context, graphql.ExecutionFinishFunc) {
+			record(name + " execution started")
+			return ctx, func(r *graphql.Result) {
+				record(name + " execution finished")
+			}
+		}
+		ext.resolveFieldDidStartFn = func(ctx context.Context, i *graphql.ResolveInfo) (context.Context, graphql.ResolveFieldFinishFunc) {
+			record(name + " field started")
+			return ctx, func(v any, err error) {
+				record(name + " field finished")
+			}
+		}
+		return ext
+	}
+
+	schema := tinit(t)
+	if err := schema.AddExtensions(tracing("first"), tracing("second"), tracing("third")); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	result := graphql.Do(graphql.Params{
+		Schema:        schema,
+		RequestString: `query Example { a }`,
+	})
+	if len(result.Errors) > 0 {
+		t.Fatalf("unexpected errors: %v", result.Errors)
+	}
+
+	expected := []string{}
+	for _, stage := range []string{"parse", "validation", "execution", "field"} {
+		expected = append(expected,
+			"first "+stage+" started",
+			"second "+stage+" started",
+			"third "+stage+" started",
+		)
+		if stage == "execution" {
+			// fields are resolved within the execution
+			continue
+		}
+		expected = append(expected,
+			"third "+stage+" finished",
+			"second "+stage+" finished",
+			"first "+stage+" finished",
+		)
+	}
+	expected = append(expected,
+		"third execution finished",
+		"second execution finished",
+		"first execution finished",
+	)
+	if !reflect.DeepEqual(expected, trace) {
+		t.Fatalf("Unexpected trace, Diff: %v", testutil.Diff(expected, trace))
+	}
+}
+
+func TestExtensionDuplicateNames(t *testing.T) {
+	expectedErr := `Schema must contain unique named extensions but contains multiple extensions named "testExt".`
+
+	schema := tinit(t)
+	_, err := graphql.NewSchema(graphql.SchemaConfig{
+		Query:      schema.QueryType(),
+		Extensions: []graphql.Extension{newtestExt("testExt"), newtestExt("testExt")},
+	})
+	if err == nil || err.Error() != expectedErr {
+		t.Fatalf("expected error %q, got: %v", expectedErr, err)
+	}
+
+	if err := schema.AddExtensions(newtestExt("testExt"), newtestExt("otherExt")); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	err = schema.AddExtensions(newtestExt("anotherExt"), newtestExt("testExt"))
+	if err == nil || err.Error() != expectedErr {
+		t.Fatalf("expected error %q, got: %v", expectedErr, err)
+	}
+
+	result := graphql.Do(graphql.Params{
+		Schema:        schema,
+		RequestString: `query Example { a }`,
+	})
+	expected := &graphql.Result{
+		Data: map[string]any{"a": "foo"},
+	}
+	if !reflect.DeepEqual(expected, result) {
+		t.Fatalf("Unexpected result, Diff: %v", testutil.Diff(expected, result))
+	}
+}
+
+func TestExtensionPartialImplementations(t *testing.T) {
+	fields := &fieldOnlyExt{}
+	schema := tinit(t)
+	if err := schema.AddExtensions(fields, resultOnlyExt{}); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	result := graphql.Do(graphql.Params{
+		Schema:        schema,
+		RequestString: `query Example { a }`,
+	})
+	expected := &graphql.Result{
+		Data: map[string]any{"a": "foo"},
+		Extensions: map[string]any{
+			"resultOnly": "result",
+		},
+	}
+	if !reflect.DeepEqual(expected, result) {
+		t.Fatalf("Unexpected result, Diff: %v", testutil.Diff(expected, result))
+	}
+	if !reflect.DeepEqual([]string{"a"}, fields.resolved) {
+		t.Fatalf("expected field a to be resolved, got: %v", fields.resolved)
+	}
+}
+
+type fieldOnlyExt struct {
+	mu       sync.Mutex
+	resolved []string
+}
+
+func (e *fieldOnlyExt) Name() string {
+	return "fieldOnly"
+}
+
+func (e *fieldOnlyExt) ResolveFieldDidStart(ctx context.Context, i *graphql.ResolveInfo) (context.Context, graphql.ResolveFieldFinishFunc) {
+	return ctx, func(v any, err error) {
+		e.mu.Lock()
+		defer e.mu.Unlock()
+		e.resolved = append(e.resolved, i.FieldName)
+	}
+}
+
+type resultOnlyExt struct{}
+
+func (resultOnlyExt) Name() string {
+	return "resultOnly"
+}
+
+func (resultOnlyExt) HasResult() bool {
+	return true
+}
+
+func (resultOnlyExt) GetResult(context.Context) any {
+	return "result"
+}
+
 func newtestExt(name string) *testExt {
 	ext := &testExt{
 		name: name,
@@ -427,6 +595,8 @@ func newtestExt(name string) *testExt {
 	return ext
 }
 
+var _ graphql.FullExtension = (*testExt)(nil)
+
 type testExt struct {
 	name                   string
 	initFn                 func(ctx context.Context, p *graphql.Params) context.Context
@@ -469,3 +639,167 @@ func (t *testExt) ExecutionDidStart(ctx context.Context) (context.Context, graph
 func (t *testExt) ResolveFieldDidStart(ctx context.Context, i *graphql.ResolveInfo) (context.Context, graphql.ResolveFieldFinishFunc) {
 	return t.resolveFieldDidStartFn(ctx, i)
 }
+
+func TestExtensionSubscriptionLifecycle(t *testing.T) {
+	var (
+		mu    sync.Mutex
+		trace []string
+	)
+	record := func(event string) {
+		mu.Lock()
+		defer mu.Unlock()
+		trace = append(trace, event)
+	}
+
+	ext := newtestSubscriptionExt("testExt")
+	ext.initFn = func(ctx context.Context, p *graphql.Params) context.Context {
+		record("init")
+		return ctx
+	}
+	ext.parseDidStartFn = func(ctx context.Context) (context.Context, graphql.ParseFinishFunc) {
+		record("parse")
+		return ctx, func(err error) {}
+	}
+	ext.validationDidStartFn = func(ctx context.Context) (context.Context, graphql.ValidationFinishFunc) {
+		record("validation")
+		return ctx, func([]gqlerrors.FormattedError) {}
+	}
+	ext.executionDidStartFn = func(ctx context.Context) (context.Context, graphql.ExecutionFinishFunc) {
+		record("execution started")
+		return ctx, func(r *graphql.Result) {
+			record(fmt.Sprintf("execution finished: %v", r.Data))
+		}
+	}
+	ext.subscriptionDidStartFn = func(ctx context.Context) (context.Context, graphql.SubscriptionFinishFunc) {
+		record("subscription started")
+		return ctx, func(err error) {
+			record(fmt.Sprintf("subscription finished: %v", err))
+		}
+	}
+
+	schema := subscriptionExtSchema(t, "a", "b")
+	schema.AddExtensions(ext)
+
+	var results []*graphql.Result
+	for result := range graphql.Subscribe(graphql.Params{
+		Schema:        schema,
+		RequestString: `subscription { sub }`,
+	}) {
+		results = append(results, result)
+	}
+	if len(results) != 2 {
+		t.Fatalf("expected 2 results, got: %v", results)
+	}
+
+	expected := []string{
+		"init",
+		"parse",
+		"validation",
+		"subscription started",
+		"execution started",
+		"execution finished: map[sub:a]",
+		"execution started",
+		"execution finished: map[sub:b]",
+		"subscription finished: <nil>",
+	}
+	if !reflect.DeepEqual(expected, trace) {
+		t.Fatalf("Unexpected trace, Diff: %v", testutil.Diff(expected, trace))
+	}
+}
+
+func TestExtensionSubscriptionDidStartPanic(t *testing.T) {
+	ext := newtestSubscriptionExt("testExt")
+	ext.subscriptionDidStartFn = func(ctx context.Context) (context.Context, graphql.SubscriptionFinishFunc) {
+		if true {
+			panic(errors.New("test error"))
+		}
+		return ctx, func(err error) {}
+	}
+
+	schema := subscriptionExtSchema(t, "a")
+	schema.AddExtensions(ext)
+
+	var results []*graphql.Result
+	for result := range graphql.Subscribe(graphql.Params{
+		Schema:        schema,
+		RequestString: `subscription { sub }`,
+	}) {
+		results = append(results, result)
+	}
+	expected := []*graphql.Result{{
+		Errors: []gqlerrors.FormattedError{
+			gqlerrors.FormatError(fmt.Errorf("%s.SubscriptionDidStart: %v", ext.Name(), errors.New("test error"))),
+		},
+	}}
+	if !reflect.DeepEqual(expected, results) {
+		t.Fatalf("Unexpected result, Diff: %v", testutil.Diff(expected, results))
+	}
+}
+
+func TestExtensionSubscriptionFinishFuncPanic(t *testing.T) {
+	ext := newtestSubscriptionExt("testExt")
+	ext.subscriptionDidStartFn = func(ctx context.Context) (context.Context, graphql.SubscriptionFinishFunc) {
+		return ctx, func(err error) {
+			panic(errors.New("test error"))
+		}
+	}
+
+	schema := subscriptionExtSchema(t, "a")
+	schema.AddExtensions(ext)
+
+	var results []*graphql.Result
+	for result := range graphql.Subscribe(graphql.Params{
+		Schema:        schema,
+		RequestString: `subscription { sub }`,
+	}) {
+		results = append(results, result)
+	}
+	expected := []*graphql.Result{
+		{
+			Data: map[string]any{"sub": "a"},
+		},
+		{
+			Errors: []gqlerrors.FormattedError{
+				gqlerrors.FormatError(fmt.Errorf("%s.SubscriptionFinishFunc: %v", ext.Name(), errors.New("test error"))),
+			},
+		},
+	}
+	if !reflect.DeepEqual(expected, results) {
+		t.Fatalf("Unexpected result, Diff: %v", testutil.Diff(expected, results))
+	}
+}
+
+func subscriptionExtSchema(t *testing.T, elements ...string) graphql.Schema {
+	return makeSubscriptionSchema(t, graphql.ObjectConfig{
+		Name: "Subscription",
+		Fields: graphql.Fields{
+			"sub": &graphql.Field{
+				Type: graphql.String,
+				Resolve: func(p graphql.ResolveParams) (any, error) {
+					return p.Source, nil
+				},
+				Subscribe: makeSubscribeToStringFunction(elements),
+			},
+		},
+	})
+}
+
+func newtestSubscriptionExt(name string) *testSubscriptionExt {
+	return &testSubscriptionExt{
+		testExt: newtestExt(name),
+		subscriptionDidStartFn: func(ctx context.Context) (context.Context, graphql.SubscriptionFinishFunc) {
+			return ctx, func(err error) {
+
+			}
+		},
+	}
+}
+
+type testSubscriptionExt struct {
+	*testExt
+	subscriptionDidStartFn func(ctx context.Context) (context.Context, graphql.SubscriptionFinishFunc)
+}
+
+func (t *testSubscriptionExt) SubscriptionDidStart(ctx context.Context) (context.Context, graphql.SubscriptionFinishFunc) {
+	return t.subscriptionDidStartFn(ctx)
+}
@@ -0,0 +1,116 @@
+package graphql_test
+
+import (
+	"reflect"
+	"sync"
+	"testing"
+
+	"github.com/dagger/graphql"
+	"github.com/dagger/graphql/testutil"
+)
+
+func fieldMiddlewareSchema(t *testing.T, middleware ...func(next graphql.FieldResolveFn) graphql.FieldResolveFn) graphql.Schema {
+	schema, err := graphql.NewSchema(graphql.SchemaConfig{
+		Query: graphql.NewObject(graphql.ObjectConfig{
+			Name: "Query",
+			Fields: graphql.Fields{
+				"echo": &graphql.Field{
+					Type: graphql.String,
+					Args: graphql.FieldConfigArgument{
+						&graphql.ArgumentConfig{Name: "message", Type: graphql.String},
+					},
+					Resolve: func(p graphql.ResolveParams) (any, error) {
+						return p.Args["message"], nil
+					},
+				},
+				"secret": &graphql.Field{
+					Type: graphql.String,
+					Resolve: func(p graphql.ResolveParams) (any, error) {
+						return "s3cr3t", nil
+					},
+				},
+				"plain": &graphql.Field{
+					Type: graphql.String,
+				},
+			},
+		}),
+		FieldMiddleware: middleware,
+	})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	return schema
+}
+
+func TestFieldMiddleware_RunsInOrder(t *testing.T) {
+	var (
+		mu    sync.Mutex
+		trace []string
+	)
+	tracing := func(name string) func(next graphql.FieldResolveFn) graphql.FieldResolveFn {
+		return func(next graphql.FieldResolveFn) graphql.FieldResolveFn {
+			return func(p graphql.ResolveParams) (any, error) {
+				mu.Lock()
+				trace = append(trace, name+" before "+p.Info.FieldName)
+				mu.Unlock()
+				result, err := next(p)
+				mu.Lock()
+				trace = append(trace, name+" after "+p.Info.FieldName)
+				mu.Unlock()
+				return result, err
+			}
+		}
+	}
+	result := graphql.Do(graphql.Params{
+		Schema:        fieldMiddlewareSchema(t, tracing("outer"), tracing("inner")),
+		RequestString: `{ plain }`,
+		RootObject:    map[string]any{"plain": "value"},
+	})
+	if len(result.Errors) > 0 {
+		t.Fatalf("unexpected errors: %v", result.Errors)
+	}
+	expected := []string{
+		"outer before plain",
+		"inner before plain",
+		"inner after plain",
+		"outer after plain",
+	}
+	if !reflect.DeepEqual(expected, trace) {
+		t.Fatalf("Unexpected trace, Diff: %v", testutil.Diff(expected, trace))
+	}
+	if data := result.Data.(map[string]any); data["plain"] != "value" {
+		t.Fatalf("expected the default resolver to run, got: %v", data)
+	}
+}
+
+func TestFieldMiddleware_CanShortCircuitAndAlterArgs(t *testing.T) {
+	middleware := func(next graphql.FieldResolveFn) graphql.FieldResolveFn {
+		return func(p graphql.ResolveParams) (any, error) {
+			switch p.Info.FieldName {
+			case "secret":
+				return "redacted", nil
+			case "echo":
+				args := map[string]any{}
+				for name, value := range p.Args {
+					args[name] = value
+				}
+				args["message"] = "altered " + args["message"].(string)
+				p.Args = args
+			}
+			return next(p)
+		}
+	}
+	result := graphql.Do(graphql.Params{
+		Schema:        fieldMiddlewareSchema(t, middleware),
+		RequestString: `{ secret echo(message: "hello") }`,
+	})
+	expected := &graphql.Result{
+		Data: map[string]any{
+			"secret": "redacted",
+			"echo":   "altered hello",
+		},
+	}
+	if !reflect.DeepEqual(expected, result) {
+		t.Fatalf("Unexpected result, Diff: %v", testutil.Diff(expected, result))
+	}
+}
@@ -0,0 +1,462 @@
+package graphql
+
+import (
+	"fmt"
+	"reflect"
+	"sort"
+
+	"github.com/dagger/graphql/language/printer"
+)
+
+// BreakingChangeType is the kind of a BreakingChange.
+type BreakingChangeType string
+
+const (
+	BreakingChangeTypeRemoved                 BreakingChangeType = "TYPE_REMOVED"
+	BreakingChangeTypeChangedKind             BreakingChangeType = "TYPE_CHANGED_KIND"
+	BreakingChangeTypeRemovedFromUnion        BreakingChangeType = "TYPE_REMOVED_FROM_UNION"
+	BreakingChangeValueRemovedFromEnum        BreakingChangeType = "VALUE_REMOVED_FROM_ENUM"
+	BreakingChangeRequiredInputFieldAdded     BreakingChangeType = "REQUIRED_INPUT_FIELD_ADDED"
+	BreakingChangeInputFieldRemoved           BreakingChangeType = "INPUT_FIELD_REMOVED"
+	BreakingChangeInputFieldChangedKind       BreakingChangeType = "INPUT_FIELD_CHANGED_KIND"
+	BreakingChangeImplementedInterfaceRemoved BreakingChangeType = "IMPLEMENTED_INTERFACE_REMOVED"
+	BreakingChangeFieldRemoved                BreakingChangeType = "FIELD_REMOVED"
+	BreakingChangeFieldChangedKind            BreakingChangeType = "FIELD_CHANGED_KIND"
+	BreakingChangeRequiredArgAdded            BreakingChangeType = "REQUIRED_ARG_ADDED"
+	BreakingChangeArgRemoved                  BreakingChangeType = "ARG_REMOVED"
+	BreakingChangeArgChangedKind              BreakingChangeType = "ARG_CHANGED_KIND"
+	BreakingChangeDirectiveRemoved            BreakingChangeType = "DIRECTIVE_REMOVED"
+	BreakingChangeDirectiveArgRemoved         BreakingChangeType = "DIRECTIVE_ARG_REMOVED"
+	BreakingChangeDirectiveArgChangedKind     BreakingChangeType = "DIRECTIVE_ARG_CHANGED_KIND"
+	BreakingChangeRequiredDirectiveArgAdded   BreakingChangeType = "REQUIRED_DIRECTIVE_ARG_ADDED"
+	BreakingChangeDirectiveLocationRemoved    BreakingChangeType = "DIRECTIVE_LOCATION_REMOVED"
+	BreakingChangeDirectiveRepeatableRemoved  BreakingChangeType = "DIRECTIVE_REPEATABLE_REMOVED"
+	BreakingChangeOperationTypeRemoved        BreakingChangeType = "OPERATION_TYPE_REMOVED"
+	BreakingChangeOperationTypeChanged        BreakingChangeType = "OPERATION_TYPE_CHANGED"
+)
+
+// DangerousChangeType is the kind of a DangerousChange.
+type DangerousChangeType string
+
+const (
+	DangerousChangeValueAddedToEnum             DangerousChangeType = "VALUE_ADDED_TO_ENUM"
+	DangerousChangeTypeAddedToUnion             DangerousChangeType = "TYPE_ADDED_TO_UNION"
+	DangerousChangeOptionalInputFieldAdded      DangerousChangeType = "OPTIONAL_INPUT_FIELD_ADDED"
+	DangerousChangeOptionalArgAdded             DangerousChangeType = "OPTIONAL_ARG_ADDED"
+	DangerousChangeImplementedInterfaceAdded    DangerousChangeType = "IMPLEMENTED_INTERFACE_ADDED"
+	DangerousChangeArgDefaultValueChange        DangerousChangeType = "ARG_DEFAULT_VALUE_CHANGE"
+	DangerousChangeInputFieldDefaultValueChange DangerousChangeType = "INPUT_FIELD_DEFAULT_VALUE_CHANGE"
+)
+
+// BreakingChange is a change between two schemas which may break clients of
+// the old schema.
+type BreakingChange struct {
+	Type        BreakingChangeType
+	Description string
+}
+
+// DangerousChange is a change between two schemas which does not break
+// clients of the old schema, but may change the behavior of their queries.
+type DangerousChange struct {
+	Type        DangerousChangeType
+	Description string
+}
+
+// FindBreakingChanges returns the changes from oldSchema to newSchema which
+// may break clients of oldSchema.
+func FindBreakingChanges(oldSchema, newSchema *Schema) []BreakingChange {
+	return findSchemaChanges(oldSchema, newSchema).breaking
+}
+
+// FindDangerousChanges returns the changes from oldSchema to newSchema which
+// may change the behavior of queries of clients of oldSchema.
+func FindDangerousChanges(oldSchema, newSchema *Schema) []DangerousChange {
+	return findSchemaChanges(oldSchema, newSchema).dangerous
+}
+
+type schemaChanges struct {
+	breaking  []BreakingChange
+	dangerous []DangerousChange
+}
+
+func (c *schemaChanges) breakingf(changeType BreakingChangeType, format string, args ...any) {
+	c.breaking = append(c.breaking, BreakingChange{
+		Type:        changeType,
+		Description: fmt.Sprintf(format, args...),
+	})
+}
+
+func (c *schemaChanges) dangerousf(changeType DangerousChangeType, format string, args ...any) {
+	c.dangerous = append(c.dangerous, DangerousChange{
+		Type:        changeType,
+		Description: fmt.Sprintf(format, args...),
+	})
+}
+
+func findSchemaChanges(oldSchema, newSchema *Schema) *schemaChanges {
+	c := &schemaChanges{}
+	c.findOperationTypeChanges(oldSchema, newSchema)
+	c.findTypeChanges(oldSchema, newSchema)
+	c.findDirectiveChanges(oldSchema, newSchema)
+	return c
+}
+
+func (c *schemaChanges) findOperationTypeChanges(oldSchema, newSchema *Schema) {
+	operations := []struct {
+		name     string
+		old, new *Object
+	}{
+		{"query", oldSchema.QueryType(), newSchema.QueryType()},
+		{"mutation", oldSchema.MutationType(), newSchema.MutationType()},
+		{"subscription", oldSchema.SubscriptionType(), newSchema.SubscriptionType()},
+	}
+	for _, operation := range operations {
+		switch {
+		case operation.old == nil:
+		case operation.new == nil:
+			c.breakingf(BreakingChangeOperationTypeRemoved, "%v operation type was removed.", operation.name)
+		case operation.old.Name() != operation.new.Name():
+			c.breakingf(BreakingChangeOperationTypeChanged, "%v operation type changed from %v to %v.",
+				operation.name, operation.old.Name(), operation.new.Name())
+		}
+	}
+}
+
+func (c *schemaChanges) findTypeChanges(oldSchema, newSchema *Schema) {
+	oldTypeMap := oldSchema.TypeMap()
+	newTypeMap := newSchema.TypeMap()
+	for _, name := range sortedTypeNames(oldTypeMap) {
+		oldType := oldTypeMap[name]
+		newType, ok := newTypeMap[name]
+		if !ok {
+			c.breakingf(BreakingChangeTypeRemoved, "%v was removed.", name)
+			continue
+		}
+		if reflect.TypeOf(oldType) != reflect.TypeOf(newType) {
+			c.breakingf(BreakingChangeTypeChangedKind, "%v changed from %v to %v.",
+				name, typeKindDescription(oldType), typeKindDescription(newType))
+			continue
+		}
+		switch oldType := oldType.(type) {
+		case *Object:
+			newType := newType.(*Object)
+			c.findImplementedInterfaceChanges(name, oldType.Interfaces(), newType.Interfaces())
+			c.findFieldChanges(name, oldType.Fields(), newType.Fields())
+		case *Interface:
+			newType := newType.(*Interface)
+			c.findImplementedInterfaceChanges(name, oldType.Interfaces(), newType.Interfaces())
+			c.findFieldChanges(name, oldType.Fields(), newType.Fields())
+		case *InputObject:
+			c.findInputFieldChanges(name, oldType.Fields(), newType.(*InputObject).Fields())
+		case *Union:
+			c.findUnionTypeChanges(name, oldType.Types(), newType.(*Union).Types())
+		case *Enum:
+			c.findEnumValueChanges(name, oldType.Values(), newType.(*Enum).Values())
+		}
+	}
+}
+
+func sortedTypeNames(typeMap TypeMap) []string {
+	names := make([]string, 0, len(typeMap))
+	for name := range typeMap {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	return names
+}
+
+func typeKindDescription(ttype Type) string {
+	switch ttype.(type) {
+	case *Scalar:
+		return "a Scalar type"
+	case *Object:
+		return "an Object type"
+	case *Interface:
+		return "an Interface type"
+	case *Union:
+		return "a Union type"
+	case *Enum:
+		return "an Enum type"
+	case *InputObject:
+		return "an Input type"
+	}
+	return fmt.Sprintf("%T", ttype)
+}
+
+func (c *schemaChanges) findImplementedInterfaceChanges(typeName string, oldInterfaces, newInterfaces []*Interface) {
+	oldNames := map[string]bool{}
+	for _, iface := range oldInterfaces {
+		oldNames[iface.Name()] = true
+	}
+	newNames := map[string]bool{}
+	for _, iface := range newInterfaces {
+		newNames[iface.Name()] = true
+	}
+	for _, iface := range oldInterfaces {
+		if !newNames[iface.Name()] {
+			c.breakingf(BreakingChangeImplementedInterfaceRemoved, "%v no longer implements interface %v.", typeName, iface.Name())
+		}
+	}
+	for _, iface := range newInterfaces {
+		if !oldNames[iface.Name()] {
+			c.dangerousf(DangerousChangeImplementedInterfaceAdded, "%v added to interfaces implemented by %v.", iface.Name(), typeName)
+		}
+	}
+}
+
+func (c *schemaChanges) findFieldChanges(typeName string, oldFields, newFields FieldDefinitionMap) {
+	names := make([]string, 0, len(oldFields))
+	for name := range oldFields {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	for _, name := range names {
+		oldField := oldFields[name]
+		newField, ok := newFields[name]
+		if !ok {
+			c.breakingf(BreakingChangeFieldRemoved, "%v.%v was removed.", typeName, name)
+			continue
+		}
+		c.findArgChanges(typeName+"."+name, oldField.Args, newField.Args, false)
+		if !isChangeSafeForOutputType(oldField.Type, newField.Type) {
+			c.breakingf(BreakingChangeFieldChangedKind, "%v.%v changed type from %v to %v.",
+				typeName, name, oldField.Type, newField.Type)
+		}
+	}
+}
+
+// findArgChanges compares the arguments of a field, or of a directive.
+func (c *schemaChanges) findArgChanges(parentName string, oldArgs, newArgs []*Argument, directive bool) {
+	removed, changedKind, requiredAdded := BreakingChangeArgRemoved, BreakingChangeArgChangedKind, BreakingChangeRequiredArgAdded
+	if directive {
+		removed, changedKind, requiredAdded = BreakingChangeDirectiveArgRemoved, BreakingChangeDirectiveArgChangedKind, BreakingChangeRequiredDirectiveArgAdded
+	}
+
+	newArgsByName := map[string]*Argument{}
+	for _, arg := range newArgs {
+		newArgsByName[arg.Name()] = arg
+	}
+	oldArgsByName := map[string]*Argument{}
+	for _, oldArg := range oldArgs {
+		oldArgsByName[oldArg.Name()] = oldArg
+		newArg, ok := newArgsByName[oldArg.Name()]
+		if !ok {
+			c.breakingf(removed, "%v arg %v was removed.", parentName, oldArg.Name())
+			continue
+		}
+		if !isChangeSafeForInputType(oldArg.Type, newArg.Type) {
+			c.breakingf(changedKind, "%v arg %v has changed type from %v to %v.",
+				parentName, oldArg.Name(), oldArg.Type, newArg.Type)
+			continue
+		}
+		if oldValue, changed := defaultValueChanged(oldArg.DefaultValue, newArg.DefaultValue, oldArg.Type); changed && !directive {
+			c.dangerousf(DangerousChangeArgDefaultValueChange, "%v arg %v has changed defaultValue from %v to %v.",
+				parentName, oldArg.Name(), oldValue, printDefaultValue(newArg.DefaultValue, newArg.Type))
+		}
+	}
+	for _, newArg := range newArgs {
+		if _, ok := oldArgsByName[newArg.Name()]; ok {
+			continue
+		}
+		if isRequiredInput(newArg.Type, newArg.DefaultValue) {
+			c.breakingf(requiredAdded, "A required arg %v on %v was added.", newArg.Name(), parentName)
+		} else if !directive {
+			c.dangerousf(DangerousChangeOptionalArgAdded, "An optional arg %v on %v was added.", newArg.Name(), parentName)
+		}
+	}
+}
+
+func (c *schemaChanges) findInputFieldChanges(typeName string, oldFields, newFields InputObjectFieldMap) {
+	names := make([]string, 0, len(oldFields))
+	for name := range oldFields {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	for _, name := range names {
+		oldField := oldFields[name]
+		newField, ok := newFields[name]
+		if !ok {
+			c.breakingf(BreakingChangeInputFieldRemoved, "%v.%v was removed.", typeName, name)
+			continue
+		}
+		if !isChangeSafeForInputType(oldField.Type, newField.Type) {
+			c.breakingf(BreakingChangeInputFieldChangedKind, "%v.%v changed type from %v to %v.",
+				typeName, name, oldField.Type, newField.Type)
+			continue
+		}
+		if oldValue, changed := defaultValueChanged(oldField.DefaultValue, newField.DefaultValue, oldField.Type); changed {
+			c.dangerousf(DangerousChangeInputFieldDefaultValueChange, "%v.%v has changed defaultValue from %v to %v.",
+				typeName, name, oldValue, printDefaultValue(newField.DefaultValue, newField.Type))
+		}
+	}
+
+	addedNames := []string{}
+	for name := range newFields {
+		if _, ok := oldFields[name]; !ok {
+			addedNames = append(addedNames, name)
+		}
+	}
+	sort.Strings(addedNames)
+	for _, name := range addedNames {
+		newField := newFields[name]
+		if isRequiredInput(newField.Type, newField.DefaultValue) {
+			c.breakingf(BreakingChangeRequiredInputFieldAdded, "A required field %v on input type %v was added.", name, typeName)
+		} else {
+			c.dangerousf(DangerousChangeOptionalInputFieldAdded, "An optional field %v on input type %v was added.", name, typeName)
+		}
+	}
+}
+
+func (c *schemaChanges) findUnionTypeChanges(typeName string, oldTypes, newTypes []*Object) {
+	oldNames := map[string]bool{}
+	for _, ttype := range oldTypes {
+		oldNames[ttype.Name()] = true
+	}
+	newNames := map[string]bool{}
+	for _, ttype := range newTypes {
+		newNames[ttype.Name()] = true
+	}
+	for _, ttype := range oldTypes {
+		if !newNames[ttype.Name()] {
+			c.breakingf(BreakingChangeTypeRemovedFromUnion, "%v was removed from union type %v.", ttype.Name(), typeName)
+		}
+	}
+	for _, ttype := range newTypes {
+		if !oldNames[ttype.Name()] {
+			c.dangerousf(DangerousChangeTypeAddedToUnion, "%v was added to union type %v.", ttype.Name(), typeName)
+		}
+	}
+}
+
+func (c *schemaChanges) findEnumValueChanges(typeName string, oldValues, newValues []*EnumValueDefinition) {
+	oldNames := map[string]bool{}
+	for _, value := range oldValues {
+		oldNames[value.Name] = true
+	}
+	newNames := map[string]bool{}
+	for _, value := range newValues {
+		newNames[value.Name] = true
+	}
+
+	removed := []string{}
+	for name := range oldNames {
+		if !newNames[name] {
+			removed = append(removed, name)
+		}
+	}
+	sort.Strings(removed)
+	for _, name := range removed {
+		c.breakingf(BreakingChangeValueRemovedFromEnum, "%v was removed from enum type %v.", name, typeName)
+	}
+
+	added := []string{}
+	for name := range newNames {
+		if !oldNames[name] {
+			added = append(added, name)
+		}
+	}
+	sort.Strings(added)
+	for _, name := range added {
+		c.dangerousf(DangerousChangeValueAddedToEnum, "%v was added to enum type %v.", name, typeName)
+	}
+}
+
+func (c *schemaChanges) findDirectiveChanges(oldSchema, newSchema *Schema) {
+	for _, oldDirective := range oldSchema.Directives() {
+		newDirective := newSchema.Directive(oldDirective.Name)
+		if newDirective == nil {
+			c.breakingf(BreakingChangeDirectiveRemoved, "%v was removed.", oldDirective.Name)
+			continue
+		}
+		c.findArgChanges(oldDirective.Name, oldDirective.Args, newDirective.Args, true)
+		if oldDirective.IsRepeatable && !newDirective.IsRepeatable {
+			c.breakingf(BreakingChangeDirectiveRepeatableRemoved, "Repeatable flag was removed from %v.", oldDirective.Name)
+		}
+
+		newLocations := map[string]bool{}
+		for _, location := range newDirective.Locations {
+			newLocations[location] = true
+		}
+		for _, location := range oldDirective.Locations {
+			if !newLocations[location] {
+				c.breakingf(BreakingChangeDirectiveLocationRemoved, "%v was removed from %v.", location, oldDirective.Name)
+			}
+		}
+	}
+}
+
+// isChangeSafeForOutputType reports whether clients expecting values of the
+// old type of a field can handle values of its new type.
+func isChangeSafeForOutputType(oldType, newType Type) bool {
+	switch oldType := oldType.(type) {
+	case *List:
+		if newType, ok := newType.(*List); ok {
+			return isChangeSafeForOutputType(oldType.OfType, newType.OfType)
+		}
+		if newType, ok := newType.(*NonNull); ok {
+			return isChangeSafeForOutputType(oldType, newType.OfType)
+		}
+		return false
+	case *NonNull:
+		if newType, ok := newType.(*NonNull); ok {
+			return isChangeSafeForOutputType(oldType.OfType, newType.OfType)
+		}
+		return false
+	}
+	if newType, ok := newType.(*NonNull); ok {
+		return isChangeSafeForOutputType(oldType, newType.OfType)
+	}
+	return isSameNamedType(oldType, newType)
+}
+
+// isChangeSafeForInputType reports whether values valid for the old type of an
+// argument or input field are valid for its new type.
+func isChangeSafeForInputType(oldType, newType Type) bool {
+	switch oldType := oldType.(type) {
+	case *List:
+		if newType, ok := newType.(*List); ok {
+			return isChangeSafeForInputType(oldType.OfType, newType.OfType)
+		}
+		return false
+	case *NonNull:
+		if newType, ok := newType.(*NonNull); ok {
+			return isChangeSafeForInputType(oldType.OfType, newType.OfType)
+		}
+		return isChangeSafeForInputType(oldType.OfType, newType)
+	}
+	return isSameNamedType(oldType, newType)
+}
+
+func isSameNamedType(oldType, newType Type) bool {
+	switch newType.(type) {
+	case *List, *NonNull:
+		return false
+	}
+	return oldType.Name() == newType.Name()
+}
+
+func isRequiredInput(ttype Type, defaultValue any) bool {
+	_, nonNull := ttype.(*NonNull)
+	return nonNull && defaultValue == nil
+}
+
+// defaultValueChanged reports whether a default value changed, returning the
+// printed old value.
+func defaultValueChanged(oldValue, newValue any, ttype Type) (string, bool) {
+	if oldValue == nil {
+		return "", false
+	}
+	oldPrinted := printDefaultValue(oldValue, ttype)
+	return oldPrinted, oldPrinted != printDefaultValue(newValue, ttype)
+}
+
+func printDefaultValue(value any, ttype Type) string {
+	if value == nil || isNullish(value) {
+		return "null"
+	}
+	valueAST := astFromValue(value, ttype)
+	if valueAST == nil {
+		return "null"
+	}
+	return fmt.Sprintf("%v", printer.Print(valueAST))
+}
@@ -0,0 +1,105 @@
+package graphql_test
+
+import (
+	"reflect"
+	"testing"
+
+	"github.com/dagger/graphql"
+	"github.com/dagger/graphql/testutil"
+)
+
+func mustBuildSchema(t *testing.T, sdl string) *graphql.Schema {
+	schema, err := graphql.BuildSchema(sdl, graphql.BuildSchemaOptions{})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	return &schema
+}
+
+const breakingChangesOldSDL = `
+interface Node { id: ID! }
+interface Named { name: String }
+type User implements Node & Named {
+  id: ID!
+  name: String
+  email: String
+  friends(first: Int = 10, after: String): [User]
+}
+type Team { id: ID! }
+union Member = User | Team
+enum Role { ADMIN EDITOR VIEWER }
+input UserFilter { role: Role, name: String }
+type Query {
+  users(filter: UserFilter): [User!]
+  node(id: ID!): Node
+  legacy: String
+}
+`
+
+const breakingChangesNewSDL = `
+interface Node { id: ID! }
+interface Named { name: String }
+type User implements Node {
+  id: ID!
+  name: String!
+  email: Int
+  friends(first: Int = 20, after: String!, last: Int): [User]
+}
+type Bot { id: ID! }
+union Member = User | Bot
+enum Role { ADMIN VIEWER OWNER }
+input UserFilter { role: Role!, name: String, active: Boolean! }
+scalar Team
+type Query {
+  users(filter: UserFilter): [User!]
+  node(id: ID!): Node
+}
+`
+
+func TestFindBreakingChanges(t *testing.T) {
+	oldSchema := mustBuildSchema(t, breakingChangesOldSDL)
+	newSchema := mustBuildSchema(t, breakingChangesNewSDL)
+
+	expected := []graphql.BreakingChange{
+		{Type: graphql.BreakingChangeTypeRemovedFromUnion, Description: "Team was removed from union type Member."},
+		{Type: graphql.BreakingChangeFieldRemoved, Description: "Query.legacy was removed."},
+		{Type: graphql.BreakingChangeValueRemovedFromEnum, Description: "EDITOR was removed from enum type Role."},
+		{Type: graphql.BreakingChangeTypeChangedKind, Description: "Team changed from an Object type to a Scalar type."},
+		{Type: graphql.BreakingChangeImplementedInterfaceRemoved, Description: "User no longer implements interface Named."},
+		{Type: graphql.BreakingChangeFieldChangedKind, Description: "User.email changed type from String to Int."},
+		{Type: graphql.BreakingChangeArgChangedKind, Description: "User.friends arg after has changed type from String to String!."},
+		{Type: graphql.BreakingChangeInputFieldChangedKind, Description: "UserFilter.role changed type from Role to Role!."},
+		{Type: graphql.BreakingChangeRequiredInputFieldAdded, Description: "A required field active on input type UserFilter was added."},
+	}
+	changes := graphql.FindBreakingChanges(oldSchema, newSchema)
+	if !reflect.DeepEqual(expected, changes) {
+		t.Fatalf("Unexpected result, Diff: %v", testutil.Diff(expected, changes))
+	}
+}
+
+func TestFindDangerousChanges(t *testing.T) {
+	oldSchema := mustBuildSchema(t, breakingChangesOldSDL)
+	newSchema := mustBuildSchema(t, breakingChangesNewSDL)
+
+	expected := []graphql.DangerousChange{
+		{Type: graphql.DangerousChangeTypeAddedToUnion, Description: "Bot was added to union type Member."},
+		{Type: graphql.DangerousChangeValueAddedToEnum, Description: "OWNER was added to enum type Role."},
+		{Type: graphql.DangerousChangeArgDefaultValueChange, Description: "User.friends arg first has changed defaultValue from 10 to 20."},
+		{Type: graphql.DangerousChangeOptionalArgAdded, Description: "An optional arg last on User.friends was added."},
+	}
+	changes := graphql.FindDangerousChanges(oldSchema, newSchema)
+	if !reflect.DeepEqual(expected, changes) {
+		t.Fatalf("Unexpected result, Diff: %v", testutil.Diff(expected, changes))
+	}
+}
+
+func TestFindBreakingChanges_NoChangesForIdenticalSchemas(t *testing.T) {
+	oldSchema := mustBuildSchema(t, breakingChangesOldSDL)
+	newSchema := mustBuildSchema(t, breakingChangesOldSDL)
+	if changes := graphql.FindBreakingChanges(oldSchema, newSchema); len(changes) != 0 {
+		t.Fatalf("expected no breaking changes, got %v", changes)
+	}
+	if changes := graphql.FindDangerousChanges(oldSchema, newSchema); len(changes) != 0 {
+		t.Fatalf("expected no dangerous changes, got %v", changes)
+	}
+}
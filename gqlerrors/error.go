@@ -25,6 +25,11 @@ func (g Error) Error() string {
 	return fmt.Sprintf("%v", g.Message)
 }
 
+// Unwrap returns the original error, if any.
+func (g Error) Unwrap() error {
+	return g.OriginalError
+}
+
 func NewError(message string, nodes []ast.Node, stack string, source *source.Source, positions []int, origError error) *Error {
 	return newError(message, nodes, stack, source, positions, nil, origError)
 }
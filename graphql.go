@@ -4,6 +4,7 @@ import (
 	"context"
 
 	"github.com/dagger/graphql/gqlerrors"
+	"github.com/dagger/graphql/language/ast"
 	"github.com/dagger/graphql/language/parser"
 	"github.com/dagger/graphql/language/source"
 )
@@ -31,9 +32,39 @@ type Params struct {
 	// Context may be provided to pass application-specific per-request
 	// information to resolve functions.
 	Context context.Context
+
+	// OrderedResults makes the executor return selection sets as *OrderedMap
+	// values, preserving the order in which fields were requested.
+	OrderedResults bool
+
+	// Parallelism is the maximum number of sibling thunks resolved
+	// concurrently at each depth of a query result. Zero or one resolves them
+	// one at a time.
+	Parallelism int
+
+	// QueryID identifies a persisted query by the hash of its document (see
+	// PersistedQueryHash). If RequestString is empty, the document is looked
+	// up in PersistedQueries, failing with a PersistedQueryNotFoundError if it
+	// is unknown. Otherwise RequestString must hash to QueryID, and is
+	// persisted once it passes validation.
+	QueryID string
+
+	// PersistedQueries stores the documents of persisted queries.
+	PersistedQueries PersistedQueryStore
+
+	// DocumentCache caches parsed and validated documents, taking precedence
+	// over the SchemaConfig.DocumentCache of the schema.
+	DocumentCache DocumentCache
 }
 
 func Do(p Params) *Result {
+	persist, err := resolvePersistedQuery(&p)
+	if err != nil {
+		return &Result{
+			Errors: gqlerrors.FormatErrors(err),
+		}
+	}
+
 	source := source.NewSource(&source.Source{
 		Body: []byte(p.RequestString),
 		Name: "GraphQL request",
@@ -47,6 +78,21 @@ func Do(p Params) *Result {
 		}
 	}
 
+	cache := p.DocumentCache
+	if cache == nil {
+		cache = p.Schema.documentCache
+	}
+	var (
+		cacheKey string
+		cached   *CachedDocument
+	)
+	if cache != nil {
+		cacheKey = documentCacheKey(&p.Schema, p.RequestString)
+		cached, _ = cache.Get(cacheKey)
+	}
+
+	// extensions are notified of parsing and validation even when the
+	// document is cached
 	extErrs, parseFinishFn := handleExtensionsParseDidStart(&p)
 	if len(extErrs) != 0 {
 		return &Result{
@@ -55,7 +101,12 @@ func Do(p Params) *Result {
 	}
 
 	// parse the source
-	AST, err := parser.Parse(parser.ParseParams{Source: source})
+	var AST *ast.Document
+	if cached != nil {
+		AST = cached.AST
+	} else {
+		AST, err = parser.Parse(parser.ParseParams{Source: source})
+	}
 	if err != nil {
 		// run parseFinishFuncs for extensions
 		extErrs = parseFinishFn(err)
@@ -75,6 +126,17 @@ func Do(p Params) *Result {
 		}
 	}
 
+	// let extensions rewrite the document, rewritten documents are validated
+	// on each request as they may be specific to it
+	parsed := AST
+	AST, extErrs = handleExtensionsRewriteDocument(&p, AST)
+	if len(extErrs) != 0 {
+		return &Result{
+			Errors: extErrs,
+		}
+	}
+	rewritten := AST != parsed
+
 	// notify extensions about the start of the validation
 	extErrs, validationFinishFn := handleExtensionsValidationDidStart(&p)
 	if len(extErrs) != 0 {
@@ -84,7 +146,21 @@ func Do(p Params) *Result {
 	}
 
 	// validate document
-	validationResult := ValidateDocument(&p.Schema, AST, nil)
+	var validationResult ValidationResult
+	if cached != nil && !rewritten {
+		validationResult = ValidationResult{
+			IsValid: len(cached.ValidationErrors) == 0,
+			Errors:  cached.ValidationErrors,
+		}
+	} else {
+		validationResult = ValidateDocument(&p.Schema, AST, nil)
+		if cache != nil && !rewritten {
+			cache.Add(cacheKey, &CachedDocument{
+				AST:              AST,
+				ValidationErrors: validationResult.Errors,
+			})
+		}
+	}
 
 	if !validationResult.IsValid {
 		// run validation finish functions for extensions
@@ -105,12 +181,33 @@ func Do(p Params) *Result {
 		}
 	}
 
+	if persist {
+		// failing to persist the document only costs the client a retry with
+		// the full document next time, so don't fail the query
+		_ = p.PersistedQueries.Put(persistedQueryContext(&p), p.QueryID, p.RequestString)
+	}
+
+	// let extensions rewrite the variables
+	extErrs = handleExtensionsRewriteVariableValues(&p)
+	if len(extErrs) != 0 {
+		return &Result{
+			Errors: extErrs,
+		}
+	}
+
+	// let extensions respond without executing the request
+	if result, ok := handleExtensionsCachedResult(&p); ok {
+		return result
+	}
+
 	return Execute(ExecuteParams{
-		Schema:        p.Schema,
-		Root:          p.RootObject,
-		AST:           AST,
-		OperationName: p.OperationName,
-		Args:          p.VariableValues,
-		Context:       p.Context,
+		Schema:         p.Schema,
+		Root:           p.RootObject,
+		AST:            AST,
+		OperationName:  p.OperationName,
+		Args:           p.VariableValues,
+		Context:        p.Context,
+		OrderedResults: p.OrderedResults,
+		Parallelism:    p.Parallelism,
 	})
 }
@@ -0,0 +1,260 @@
+package graphql
+
+import (
+	"context"
+	"fmt"
+	"sync"
+
+	"github.com/dagger/graphql/gqlerrors"
+	"github.com/dagger/graphql/language/ast"
+)
+
+// IncrementalResult is a subsequent payload of an operation executed with
+// ExecuteIncremental. It holds either the data of a deferred fragment, or
+// items of a streamed list field.
+type IncrementalResult struct {
+	// Data is the result of a deferred fragment, to be merged into the object
+	// found at Path.
+	Data any `json:"data,omitempty"`
+
+	// Items are streamed list items, to be appended to the list found at Path
+	// without its last element, which is the index of the first item.
+	Items []any `json:"items,omitempty"`
+
+	Path    []any                      `json:"path"`
+	Label   string                     `json:"label,omitempty"`
+	Errors  []gqlerrors.FormattedError `json:"errors,omitempty"`
+	HasNext bool                       `json:"hasNext"`
+}
+
+// ExecuteIncremental executes an operation which may use the @defer and
+// @stream directives (see IncrementalDirectives). It returns the initial
+// result, holding everything but the deferred fragments and streamed items,
+// and a channel of the subsequent payloads delivering them. The channel is
+// closed once every payload was sent, the last one having HasNext set to
+// false; it is closed right away if nothing was deferred.
+func ExecuteIncremental(p ExecuteParams) (*Result, <-chan *IncrementalResult) {
+	ctx := p.Context
+	if ctx == nil {
+		ctx = context.Background()
+	}
+
+	incremental := &incrementalState{}
+	p.incremental = incremental
+	result := Execute(p)
+
+	payloads := make(chan *IncrementalResult)
+	go func() {
+		defer close(payloads)
+		for {
+			job := incremental.shift()
+			if job == nil {
+				return
+			}
+			payload := job()
+			payload.HasNext = incremental.hasNext()
+			select {
+			case payloads <- payload:
+			case <-ctx.Done():
+				return
+			}
+		}
+	}()
+	return result, payloads
+}
+
+// incrementalState holds the deferred work of an operation executed with
+// ExecuteIncremental, in the order it is to be delivered.
+type incrementalState struct {
+	mu   sync.Mutex
+	jobs []func() *IncrementalResult
+}
+
+func (s *incrementalState) push(job func() *IncrementalResult) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.jobs = append(s.jobs, job)
+}
+
+func (s *incrementalState) shift() func() *IncrementalResult {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if len(s.jobs) == 0 {
+		return nil
+	}
+	job := s.jobs[0]
+	s.jobs = s.jobs[1:]
+	return job
+}
+
+func (s *incrementalState) hasNext() bool {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return len(s.jobs) > 0
+}
+
+// deferredFragment is a fragment whose fields were left out of a selection set
+// because of @defer.
+type deferredFragment struct {
+	label        string
+	runtimeType  *Object
+	selectionSet *ast.SelectionSet
+}
+
+// getDeferValues returns the @defer directive applied to a fragment, if any and
+// its `if` argument is true.
+func getDeferValues(eCtx *executionContext, directives []*ast.Directive) (label string, ok bool) {
+	argValues, ok := getIncrementalArgumentValues(eCtx, DeferDirective, directives)
+	if !ok {
+		return "", false
+	}
+	label, _ = argValues["label"].(string)
+	return label, true
+}
+
+// getStreamValues returns the @stream directive applied to a field, if any and
+// its `if` argument is true.
+func getStreamValues(eCtx *executionContext, fieldASTs []*ast.Field) (label string, initialCount int, ok bool) {
+	if len(fieldASTs) == 0 {
+		return "", 0, false
+	}
+	argValues, ok := getIncrementalArgumentValues(eCtx, StreamDirective, fieldASTs[0].Directives)
+	if !ok {
+		return "", 0, false
+	}
+	label, _ = argValues["label"].(string)
+	// literals are parsed to int64, variables may hold any kind of int
+	switch count := argValues["initialCount"].(type) {
+	case int:
+		initialCount = count
+	case int32:
+		initialCount = int(count)
+	case int64:
+		initialCount = int(count)
+	}
+	if initialCount < 0 {
+		initialCount = 0
+	}
+	return label, initialCount, true
+}
+
+func getIncrementalArgumentValues(eCtx *executionContext, directive *Directive, directives []*ast.Directive) (map[string]any, bool) {
+	if eCtx.incremental == nil {
+		return nil, false
+	}
+	for _, directiveAST := range directives {
+		if directiveAST == nil || directiveAST.Name == nil || directiveAST.Name.Value != directive.Name {
+			continue
+		}
+		argValues := getArgumentValues(directive.Args, directiveAST.Arguments, eCtx.VariableValues)
+		if enabled, ok := argValues["if"].(bool); ok && !enabled {
+			return nil, false
+		}
+		return argValues, true
+	}
+	return nil, false
+}
+
+// forkIncremental returns an execution context for a subsequent payload, which
+// collects its own errors.
+func (eCtx *executionContext) forkIncremental() *executionContext {
+	return &executionContext{
+		Schema:         eCtx.Schema,
+		Fragments:      eCtx.Fragments,
+		Root:           eCtx.Root,
+		Operation:      eCtx.Operation,
+		VariableValues: eCtx.VariableValues,
+		Context:        eCtx.Context,
+		OrderedResults: eCtx.OrderedResults,
+		Parallelism:    eCtx.Parallelism,
+		Loaders:        eCtx.Loaders,
+		incremental:    eCtx.incremental,
+	}
+}
+
+// deferFragments schedules the execution of deferred fragments of the object
+// found at path, whose fields are resolved with the given context.
+func (eCtx *executionContext) deferFragments(ctx context.Context, fragments []*deferredFragment, source any, path *ResponsePath) {
+	for _, fragment := range fragments {
+		fragment := fragment
+		eCtx.incremental.push(func() *IncrementalResult {
+			fragmentCtx := eCtx.forkIncremental()
+			payload := &IncrementalResult{
+				Path:  responsePathArray(path),
+				Label: fragment.label,
+			}
+			func() {
+				defer recoverIncrementalError(fragmentCtx, path)
+
+				deferred := []*deferredFragment{}
+				fields := collectFields(collectFieldsParams{
+					ExeContext:   fragmentCtx,
+					RuntimeType:  fragment.runtimeType,
+					SelectionSet: fragment.selectionSet,
+					Deferred:     &deferred,
+				})
+				data := executeSubFields(executeFieldsParams{
+					ExecutionContext: fragmentCtx,
+					Context:          ctx,
+					ParentType:       fragment.runtimeType,
+					Source:           source,
+					Fields:           fields,
+					Path:             path,
+				})
+				fragmentCtx.deferFragments(ctx, deferred, source, path)
+				dethunkResults(fragmentCtx, data)
+				payload.Data = data
+			}()
+			payload.Errors = fragmentCtx.Errors
+			return payload
+		})
+	}
+}
+
+// streamItems schedules the completion of the remaining items of a streamed
+// list, each delivered in its own payload.
+func (eCtx *executionContext) streamItems(ctx context.Context, label string, itemType Type, fieldASTs []*ast.Field, info ResolveInfo, path *ResponsePath, items []any, start int) {
+	for i, item := range items {
+		itemPath := path.WithKey(start + i)
+		item := item
+		eCtx.incremental.push(func() *IncrementalResult {
+			itemCtx := eCtx.forkIncremental()
+			payload := &IncrementalResult{
+				Path:  responsePathArray(itemPath),
+				Label: label,
+			}
+			func() {
+				defer recoverIncrementalError(itemCtx, itemPath)
+
+				completed := []any{completeValueCatchingError(itemCtx, ctx, itemType, fieldASTs, info, itemPath, item)}
+				dethunkResults(itemCtx, completed)
+				payload.Items = completed
+			}()
+			payload.Errors = itemCtx.Errors
+			return payload
+		})
+	}
+}
+
+// recoverIncrementalError records a null propagated up to the root of a
+// subsequent payload.
+func recoverIncrementalError(eCtx *executionContext, path *ResponsePath) {
+	if r := recover(); r != nil {
+		err, ok := r.(error)
+		if !ok {
+			err = fmt.Errorf("%v", r)
+		}
+		if _, ok := err.(gqlerrors.FormattedError); ok {
+			eCtx.addErrors(gqlerrors.FormatError(err))
+			return
+		}
+		eCtx.addErrors(gqlerrors.FormatError(NewLocatedErrorWithPath(err, nil, path.AsArray())))
+	}
+}
+
+func responsePathArray(path *ResponsePath) []any {
+	if path == nil {
+		return []any{}
+	}
+	return path.AsArray()
+}
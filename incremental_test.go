@@ -0,0 +1,196 @@
+package graphql_test
+
+import (
+	"encoding/json"
+	"errors"
+	"reflect"
+	"testing"
+
+	"github.com/dagger/graphql"
+	"github.com/dagger/graphql/language/parser"
+	"github.com/dagger/graphql/testutil"
+)
+
+func incrementalSchema(t *testing.T) graphql.Schema {
+	friendType := graphql.NewObject(graphql.ObjectConfig{
+		Name: "Friend",
+		Fields: graphql.Fields{
+			"name": &graphql.Field{Type: graphql.String},
+		},
+	})
+	heroType := graphql.NewObject(graphql.ObjectConfig{
+		Name: "Hero",
+		Fields: graphql.Fields{
+			"id":   &graphql.Field{Type: graphql.String},
+			"name": &graphql.Field{Type: graphql.String},
+			"friends": &graphql.Field{
+				Type: graphql.NewList(friendType),
+			},
+			"secret": &graphql.Field{
+				Type: graphql.NewNonNull(graphql.String),
+				Resolve: func(p graphql.ResolveParams) (any, error) {
+					return nil, errors.New("secret is classified")
+				},
+			},
+		},
+	})
+	schema, err := graphql.NewSchema(graphql.SchemaConfig{
+		Query: graphql.NewObject(graphql.ObjectConfig{
+			Name: "Query",
+			Fields: graphql.Fields{
+				"hero": &graphql.Field{
+					Type: heroType,
+					Resolve: func(p graphql.ResolveParams) (any, error) {
+						return map[string]any{
+							"id":   "1",
+							"name": "Luke",
+							"friends": []any{
+								map[string]any{"name": "Han"},
+								map[string]any{"name": "Leia"},
+								map[string]any{"name": "C-3PO"},
+							},
+						}, nil
+					},
+				},
+			},
+		}),
+		Directives: append(graphql.SpecifiedDirectives, graphql.IncrementalDirectives...),
+	})
+	if err != nil {
+		t.Fatalf("Error in schema %v", err.Error())
+	}
+	return schema
+}
+
+func executeIncremental(t *testing.T, schema graphql.Schema, query string) (*graphql.Result, []*graphql.IncrementalResult) {
+	ast := testutil.TestParse(t, query)
+	validation := graphql.ValidateDocument(&schema, ast, nil)
+	if !validation.IsValid {
+		t.Fatalf("unexpected validation errors: %v", validation.Errors)
+	}
+	result, payloads := graphql.ExecuteIncremental(graphql.ExecuteParams{
+		Schema: schema,
+		AST:    ast,
+	})
+	var incremental []*graphql.IncrementalResult
+	for payload := range payloads {
+		incremental = append(incremental, payload)
+	}
+	return result, incremental
+}
+
+func TestExecuteIncremental_DefersFragments(t *testing.T) {
+	result, payloads := executeIncremental(t, incrementalSchema(t), `
+		query {
+			hero {
+				id
+				...HeroName @defer(label: "name")
+			}
+		}
+		fragment HeroName on Hero { name }
+	`)
+
+	expected := &graphql.Result{
+		Data: map[string]any{
+			"hero": map[string]any{"id": "1"},
+		},
+	}
+	if !reflect.DeepEqual(expected, result) {
+		t.Fatalf("Unexpected result, Diff: %v", testutil.Diff(expected, result))
+	}
+	expectedPayloads := []*graphql.IncrementalResult{
+		{
+			Data:    map[string]any{"name": "Luke"},
+			Path:    []any{"hero"},
+			Label:   "name",
+			HasNext: false,
+		},
+	}
+	if !reflect.DeepEqual(expectedPayloads, payloads) {
+		t.Fatalf("Unexpected payloads, Diff: %v", testutil.Diff(expectedPayloads, payloads))
+	}
+}
+
+func TestExecuteIncremental_StreamsListItems(t *testing.T) {
+	result, payloads := executeIncremental(t, incrementalSchema(t), `
+		query {
+			hero {
+				friends @stream(initialCount: 1, label: "friends") { name }
+			}
+		}
+	`)
+
+	expected := &graphql.Result{
+		Data: map[string]any{
+			"hero": map[string]any{
+				"friends": []any{map[string]any{"name": "Han"}},
+			},
+		},
+	}
+	if !reflect.DeepEqual(expected, result) {
+		t.Fatalf("Unexpected result, Diff: %v", testutil.Diff(expected, result))
+	}
+	expectedPayloads := []*graphql.IncrementalResult{
+		{
+			Items:   []any{map[string]any{"name": "Leia"}},
+			Path:    []any{"hero", "friends", 1},
+			Label:   "friends",
+			HasNext: true,
+		},
+		{
+			Items:   []any{map[string]any{"name": "C-3PO"}},
+			Path:    []any{"hero", "friends", 2},
+			Label:   "friends",
+			HasNext: false,
+		},
+	}
+	if !reflect.DeepEqual(expectedPayloads, payloads) {
+		t.Fatalf("Unexpected payloads, Diff: %v", testutil.Diff(expectedPayloads, payloads))
+	}
+}
+
+func TestExecuteIncremental_NullInDeferredFragmentOnlyAffectsItsPayload(t *testing.T) {
+	result, payloads := executeIncremental(t, incrementalSchema(t), `
+		query {
+			hero {
+				id
+				... @defer { secret }
+			}
+		}
+	`)
+
+	if len(result.Errors) != 0 {
+		t.Fatalf("unexpected errors in initial result: %v", result.Errors)
+	}
+	if len(payloads) != 1 {
+		t.Fatalf("expected 1 payload, got %d", len(payloads))
+	}
+	b, err := json.Marshal(payloads[0])
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	expected := `{"path":["hero"],"errors":[{"message":"secret is classified","locations":[{"line":5,"column":18}],"path":["hero","secret"]}],"hasNext":false}`
+	if string(b) != expected {
+		t.Fatalf("wrong payload, expected %s, got %s", expected, b)
+	}
+}
+
+func TestExecuteIncremental_IgnoredByExecute(t *testing.T) {
+	schema := incrementalSchema(t)
+	ast, err := parser.Parse(parser.ParseParams{Source: `{ hero { id ... @defer { name } } }`})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	result := graphql.Execute(graphql.ExecuteParams{
+		Schema: schema,
+		AST:    ast,
+	})
+	expected := &graphql.Result{
+		Data: map[string]any{
+			"hero": map[string]any{"id": "1", "name": "Luke"},
+		},
+	}
+	if !reflect.DeepEqual(expected, result) {
+		t.Fatalf("Unexpected result, Diff: %v", testutil.Diff(expected, result))
+	}
+}
@@ -0,0 +1,206 @@
+package graphql_test
+
+import (
+	"reflect"
+	"strings"
+	"testing"
+
+	"github.com/dagger/graphql"
+	"github.com/dagger/graphql/testutil"
+)
+
+const nestedInterfacesSDL = `
+interface Node {
+  id: ID!
+}
+
+interface Resource implements Node {
+  id: ID!
+  url: String
+}
+
+type File implements Resource & Node {
+  id: ID!
+  url: String
+  size: Int
+}
+
+type Query {
+  node: Node
+  resource: Resource
+}
+`
+
+func nestedInterfacesSchema(t *testing.T) graphql.Schema {
+	file := map[string]any{
+		"__typename": "File",
+		"id":         "1",
+		"url":        "https://example.com/file",
+		"size":       42,
+	}
+	resolve := func(p graphql.ResolveParams) (any, error) {
+		return file, nil
+	}
+	schema, err := graphql.BuildSchema(nestedInterfacesSDL, graphql.BuildSchemaOptions{
+		Resolvers: map[string]map[string]graphql.FieldResolveFn{
+			"Query": {
+				"node":     resolve,
+				"resource": resolve,
+			},
+		},
+	})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	return schema
+}
+
+func TestInterfaceInterfaces_ImplementationsAreTransitive(t *testing.T) {
+	schema := nestedInterfacesSchema(t)
+
+	resource := schema.Type("Resource").(*graphql.Interface)
+	if interfaces := resource.Interfaces(); len(interfaces) != 1 || interfaces[0].Name() != "Node" {
+		t.Fatalf("unexpected interfaces: %v", interfaces)
+	}
+	file := schema.Type("File").(*graphql.Object)
+	for _, name := range []string{"Node", "Resource"} {
+		if !schema.IsPossibleType(schema.Type(name).(*graphql.Interface), file) {
+			t.Fatalf("expected File to be a possible type of %v", name)
+		}
+	}
+}
+
+func TestInterfaceInterfaces_InterfacesThunk(t *testing.T) {
+	var node *graphql.Interface
+	resource := graphql.NewInterface(graphql.InterfaceConfig{
+		Name: "Resource",
+		Interfaces: graphql.InterfacesThunk(func() []*graphql.Interface {
+			return []*graphql.Interface{node}
+		}),
+		Fields: graphql.Fields{
+			"id": &graphql.Field{Type: graphql.ID},
+		},
+	})
+	node = graphql.NewInterface(graphql.InterfaceConfig{
+		Name: "Node",
+		Fields: graphql.Fields{
+			"id": &graphql.Field{Type: graphql.ID},
+		},
+	})
+	if interfaces := resource.Interfaces(); len(interfaces) != 1 || interfaces[0] != node {
+		t.Fatalf("unexpected interfaces: %v", interfaces)
+	}
+}
+
+func TestInterfaceInterfaces_RejectsMissingTransitiveInterface(t *testing.T) {
+	_, err := graphql.BuildSchema(`
+interface Node { id: ID! }
+interface Resource implements Node { id: ID! }
+type File implements Resource { id: ID! }
+type Query { file: File }
+`, graphql.BuildSchemaOptions{})
+	expected := `Type File must implement Node because it is implemented by Resource.`
+	if err == nil || err.Error() != expected {
+		t.Fatalf("expected error %q, got: %v", expected, err)
+	}
+}
+
+func TestInterfaceInterfaces_RejectsCircularReferences(t *testing.T) {
+	_, err := graphql.BuildSchema(`
+interface A implements B { id: ID! }
+interface B implements A { id: ID! }
+type Query { a: A }
+`, graphql.BuildSchemaOptions{})
+	if err == nil {
+		t.Fatalf("expected an error for circular interfaces")
+	}
+}
+
+func TestInterfaceInterfaces_RejectsMissingInterfaceField(t *testing.T) {
+	_, err := graphql.BuildSchema(`
+interface Node { id: ID! }
+interface Resource implements Node { url: String }
+type Query { resource: Resource }
+`, graphql.BuildSchemaOptions{})
+	expected := `"Node" expects field "id" but "Resource" does not provide it.`
+	if err == nil || err.Error() != expected {
+		t.Fatalf("expected error %q, got: %v", expected, err)
+	}
+}
+
+func TestInterfaceInterfaces_IntrospectsInterfacesOfInterfaces(t *testing.T) {
+	schema := nestedInterfacesSchema(t)
+	result := graphql.Do(graphql.Params{
+		Schema: schema,
+		RequestString: `{
+			__type(name: "Resource") {
+				interfaces { name }
+				possibleTypes { name }
+			}
+		}`,
+	})
+	expected := &graphql.Result{
+		Data: map[string]any{
+			"__type": map[string]any{
+				"interfaces": []any{
+					map[string]any{"name": "Node"},
+				},
+				"possibleTypes": []any{
+					map[string]any{"name": "File"},
+				},
+			},
+		},
+	}
+	if !reflect.DeepEqual(expected, result) {
+		t.Fatalf("Unexpected result, Diff: %v", testutil.Diff(expected, result))
+	}
+}
+
+func TestInterfaceInterfaces_ExecutesFragmentsOnNestedInterfaces(t *testing.T) {
+	schema := nestedInterfacesSchema(t)
+	result := graphql.Do(graphql.Params{
+		Schema: schema,
+		RequestString: `{
+			node {
+				id
+				... on Resource { url }
+				... on File { size }
+			}
+			resource {
+				...NodeFields
+			}
+		}
+		fragment NodeFields on Node { id }`,
+	})
+	expected := &graphql.Result{
+		Data: map[string]any{
+			"node": map[string]any{
+				"id":   "1",
+				"url":  "https://example.com/file",
+				"size": int64(42),
+			},
+			"resource": map[string]any{
+				"id": "1",
+			},
+		},
+	}
+	if !reflect.DeepEqual(expected, result) {
+		t.Fatalf("Unexpected result, Diff: %v", testutil.Diff(expected, result))
+	}
+}
+
+func TestInterfaceInterfaces_PrintsImplementedInterfaces(t *testing.T) {
+	schema := nestedInterfacesSchema(t)
+	printed := graphql.PrintSchema(&schema)
+	rebuilt, err := graphql.BuildSchema(printed, graphql.BuildSchemaOptions{})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if reprinted := graphql.PrintSchema(&rebuilt); reprinted != printed {
+		t.Fatalf("Unexpected result, Diff: %v", testutil.Diff(printed, reprinted))
+	}
+	expected := `interface Resource implements Node {`
+	if !strings.Contains(printed, expected) {
+		t.Fatalf("expected %q in:\n%v", expected, printed)
+	}
+}
@@ -234,6 +234,15 @@ func init() {
 			"enumValues":    &Field{},
 			"inputFields":   &Field{},
 			"ofType":        &Field{},
+			"isOneOf": &Field{
+				Type: Boolean,
+				Resolve: func(p ResolveParams) (any, error) {
+					if ttype, ok := p.Source.(*InputObject); ok {
+						return ttype.IsOneOf(), nil
+					}
+					return nil, nil
+				},
+			},
 		},
 	})
 
@@ -351,6 +360,9 @@ func init() {
 					NewNonNull(InputValueType),
 				)),
 			},
+			"isRepeatable": &Field{
+				Type: NewNonNull(Boolean),
+			},
 			// NOTE: the following three fields are deprecated and are no longer part
 			// of the GraphQL specification.
 			"onOperation": &Field{
@@ -579,7 +591,10 @@ func init() {
 	TypeType.AddFieldConfig("interfaces", &Field{
 		Type: NewList(NewNonNull(TypeType)),
 		Resolve: func(p ResolveParams) (any, error) {
-			if ttype, ok := p.Source.(*Object); ok {
+			switch ttype := p.Source.(type) {
+			case *Object:
+				return ttype.Interfaces(), nil
+			case *Interface:
 				return ttype.Interfaces(), nil
 			}
 			return nil, nil
@@ -759,8 +774,93 @@ func astFromValue(value any, ttype Type) ast.Value {
 		return val
 	}
 
-	if valueVal.Type().Kind() == reflect.Map {
-		// TODO: implement astFromValue from Map to Value
+	// Values of unknown type, such as the arguments of applied directives not
+	// defined by the schema, are printed according to their Go type.
+	if ttype == nil {
+		switch valueVal.Kind() {
+		case reflect.Slice:
+			values := []ast.Value{}
+			for i := 0; i < valueVal.Len(); i++ {
+				if itemAST := astFromValue(valueVal.Index(i).Interface(), nil); itemAST != nil {
+					values = append(values, itemAST)
+				}
+			}
+			return ast.NewListValue(&ast.ListValue{
+				Values: values,
+			})
+		case reflect.Map:
+			fieldASTs := []*ast.ObjectField{}
+			for iter := valueVal.MapRange(); iter.Next(); {
+				// only keys of a string kind can name the fields of an object
+				key := iter.Key()
+				if key.Kind() == reflect.Interface {
+					key = key.Elem()
+				}
+				if key.Kind() != reflect.String {
+					continue
+				}
+				fieldValue := astFromValue(iter.Value().Interface(), nil)
+				if fieldValue == nil {
+					continue
+				}
+				fieldASTs = append(fieldASTs, ast.NewObjectField(&ast.ObjectField{
+					Name:  ast.NewName(&ast.Name{Value: key.String()}),
+					Value: fieldValue,
+				}))
+			}
+			sort.Slice(fieldASTs, func(i, j int) bool {
+				return fieldASTs[i].Name.Value < fieldASTs[j].Name.Value
+			})
+			return ast.NewObjectValue(&ast.ObjectValue{
+				Fields: fieldASTs,
+			})
+		}
+	}
+
+	// Populate the fields of the input object by creating ASTs from each value
+	// in the Golang map according to the fields in the input type.
+	if ttype, ok := ttype.(*InputObject); ok && valueVal.Type().Kind() == reflect.Map {
+		keyType := valueVal.Type().Key()
+		fieldASTs := []*ast.ObjectField{}
+		fields := ttype.Fields()
+		fieldNames := make([]string, 0, len(fields))
+		for fieldName := range fields {
+			fieldNames = append(fieldNames, fieldName)
+		}
+		sort.Strings(fieldNames)
+		for _, fieldName := range fieldNames {
+			key := reflect.ValueOf(fieldName)
+			if !key.Type().AssignableTo(keyType) {
+				if keyType.Kind() != reflect.String {
+					continue
+				}
+				key = key.Convert(keyType)
+			}
+			fieldVal := valueVal.MapIndex(key)
+			if !fieldVal.IsValid() {
+				continue
+			}
+			fieldValue := astFromValue(fieldVal.Interface(), fields[fieldName].Type)
+			if fieldValue == nil {
+				continue
+			}
+			fieldASTs = append(fieldASTs, ast.NewObjectField(&ast.ObjectField{
+				Name:  ast.NewName(&ast.Name{Value: fieldName}),
+				Value: fieldValue,
+			}))
+		}
+		return ast.NewObjectValue(&ast.ObjectValue{
+			Fields: fieldASTs,
+		})
+	}
+
+	// Enum values are printed by name, which may differ from their internal value.
+	if ttype, ok := ttype.(*Enum); ok {
+		if name, err := ttype.Serialize(value); err == nil && name != nil {
+			return ast.NewEnumValue(&ast.EnumValue{
+				Value: fmt.Sprintf("%v", name),
+			})
+		}
 	}
 
 	if value, ok := value.(bool); ok {
@@ -778,6 +878,13 @@ func astFromValue(value any, ttype Type) ast.Value {
 			Value: fmt.Sprintf("%v", value),
 		})
 	}
+	switch valueVal.Kind() {
+	case reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64,
+		reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64:
+		return ast.NewIntValue(&ast.IntValue{
+			Value: fmt.Sprintf("%v", valueVal.Interface()),
+		})
+	}
 	if value, ok := value.(float32); ok {
 		return ast.NewFloatValue(&ast.FloatValue{
 			Value: fmt.Sprintf("%v", value),
@@ -193,6 +193,7 @@ type DirectiveDefinition struct {
 	Name        *Name
 	Description *StringValue
 	Arguments   []*InputValueDefinition
+	Repeatable  bool
 	Locations   []*Name
 }
 
@@ -206,6 +207,7 @@ func NewDirectiveDefinition(def *DirectiveDefinition) *DirectiveDefinition {
 		Name:        def.Name,
 		Description: def.Description,
 		Arguments:   def.Arguments,
+		Repeatable:  def.Repeatable,
 		Locations:   def.Locations,
 	}
 }
@@ -292,6 +292,7 @@ type InterfaceDefinition struct {
 	Loc         *Location
 	Name        *Name
 	Description *StringValue
+	Interfaces  []*Named
 	Directives  []*Directive
 	Fields      []*FieldDefinition
 }
@@ -305,6 +306,7 @@ func NewInterfaceDefinition(def *InterfaceDefinition) *InterfaceDefinition {
 		Loc:         def.Loc,
 		Name:        def.Name,
 		Description: def.Description,
+		Interfaces:  def.Interfaces,
 		Directives:  def.Directives,
 		Fields:      def.Fields,
 	}
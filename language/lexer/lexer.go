@@ -78,6 +78,7 @@ const (
 	INPUT        = "input"
 	EXTEND       = "extend"
 	DIRECTIVE    = "directive"
+	REPEATABLE   = "repeatable"
 )
 
 // Token is a representation of a lexed Token. Value only appears for non-punctuation
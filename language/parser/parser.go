@@ -1164,6 +1164,10 @@ func parseInterfaceTypeDefinition(parser *Parser) (ast.Node, error) {
 	if err != nil {
 		return nil, err
 	}
+	interfaces, err := parseImplementsInterfaces(parser)
+	if err != nil {
+		return nil, err
+	}
 	directives, err := parseDirectives(parser)
 	if err != nil {
 		return nil, err
@@ -1184,6 +1188,7 @@ func parseInterfaceTypeDefinition(parser *Parser) (ast.Node, error) {
 	return ast.NewInterfaceDefinition(&ast.InterfaceDefinition{
 		Name:        name,
 		Description: description,
+		Interfaces:  interfaces,
 		Directives:  directives,
 		Loc:         loc(parser, start),
 		Fields:      fields,
@@ -1394,6 +1399,7 @@ func parseDirectiveDefinition(parser *Parser) (ast.Node, error) {
 		description *ast.StringValue
 		name        *ast.Name
 		args        []*ast.InputValueDefinition
+		repeatable  bool
 		locations   []*ast.Name
 	)
 	start := parser.Token.Start
@@ -1412,6 +1418,12 @@ func parseDirectiveDefinition(parser *Parser) (ast.Node, error) {
 	if args, err = parseArgumentDefs(parser); err != nil {
 		return nil, err
 	}
+	if parser.Token.Kind == lexer.NAME && parser.Token.Value == lexer.REPEATABLE {
+		if err = advance(parser); err != nil {
+			return nil, err
+		}
+		repeatable = true
+	}
 	if _, err = expectKeyWord(parser, "on"); err != nil {
 		return nil, err
 	}
@@ -1424,6 +1436,7 @@ func parseDirectiveDefinition(parser *Parser) (ast.Node, error) {
 		Name:        name,
 		Description: description,
 		Arguments:   args,
+		Repeatable:  repeatable,
 		Locations:   locations,
 	}), nil
 }
@@ -361,6 +361,7 @@ interface Hello {
 					Value: "Hello",
 					Loc:   testLoc(11, 16),
 				}),
+				Interfaces: []*ast.Named{},
 				Directives: []*ast.Directive{},
 				Fields: []*ast.FieldDefinition{
 					ast.NewFieldDefinition(&ast.FieldDefinition{
@@ -388,6 +389,64 @@ interface Hello {
 	}
 }
 
+func TestSchemaParser_InterfaceImplementingInterfaces(t *testing.T) {
+	body := `
+interface Hello implements World & Node {
+  world: String
+}`
+	astDoc := parse(t, body)
+	expected := ast.NewDocument(&ast.Document{
+		Loc: testLoc(1, 60),
+		Definitions: []ast.Node{
+			ast.NewInterfaceDefinition(&ast.InterfaceDefinition{
+				Loc: testLoc(1, 60),
+				Name: ast.NewName(&ast.Name{
+					Value: "Hello",
+					Loc:   testLoc(11, 16),
+				}),
+				Interfaces: []*ast.Named{
+					ast.NewNamed(&ast.Named{
+						Name: ast.NewName(&ast.Name{
+							Value: "World",
+							Loc:   testLoc(28, 33),
+						}),
+						Loc: testLoc(28, 33),
+					}),
+					ast.NewNamed(&ast.Named{
+						Name: ast.NewName(&ast.Name{
+							Value: "Node",
+							Loc:   testLoc(36, 40),
+						}),
+						Loc: testLoc(36, 40),
+					}),
+				},
+				Directives: []*ast.Directive{},
+				Fields: []*ast.FieldDefinition{
+					ast.NewFieldDefinition(&ast.FieldDefinition{
+						Loc: testLoc(45, 58),
+						Name: ast.NewName(&ast.Name{
+							Value: "world",
+							Loc:   testLoc(45, 50),
+						}),
+						Directives: []*ast.Directive{},
+						Arguments:  []*ast.InputValueDefinition{},
+						Type: ast.NewNamed(&ast.Named{
+							Loc: testLoc(52, 58),
+							Name: ast.NewName(&ast.Name{
+								Value: "String",
+								Loc:   testLoc(52, 58),
+							}),
+						}),
+					}),
+				},
+			}),
+		},
+	})
+	if !reflect.DeepEqual(astDoc, expected) {
+		t.Fatalf("unexpected document, expected: %v, got: %v", expected, astDoc)
+	}
+}
+
 func TestSchemaParser_SimpleFieldWithArg(t *testing.T) {
 	body := `
 type Hello {
@@ -692,6 +692,7 @@ var printDocASTReducer = map[string]visitor.VisitFunc{
 		switch node := p.Node.(type) {
 		case *ast.InterfaceDefinition:
 			name := fmt.Sprintf("%v", node.Name)
+			interfaces := toSliceString(node.Interfaces)
 			fields := node.Fields
 			directives := []string{}
 			for _, directive := range node.Directives {
@@ -700,6 +701,7 @@ var printDocASTReducer = map[string]visitor.VisitFunc{
 			str := join([]string{
 				"interface",
 				name,
+				wrap("implements ", join(interfaces, " & "), ""),
 				join(directives, " "),
 				block(fields),
 			}, " ")
@@ -709,6 +711,7 @@ var printDocASTReducer = map[string]visitor.VisitFunc{
 			return visitor.ActionUpdate, str
 		case map[string]any:
 			name := getMapValueString(node, "Name")
+			interfaces := toSliceString(getMapValue(node, "Interfaces"))
 			fields := getMapValue(node, "Fields")
 			directives := []string{}
 			for _, directive := range getMapSliceValue(node, "Directives") {
@@ -717,6 +720,7 @@ var printDocASTReducer = map[string]visitor.VisitFunc{
 			str := join([]string{
 				"interface",
 				name,
+				wrap("implements ", join(interfaces, " & "), ""),
 				join(directives, " "),
 				block(fields),
 			}, " ")
@@ -907,7 +911,11 @@ var printDocASTReducer = map[string]visitor.VisitFunc{
 			} else {
 				argsStr = wrap("(", join(args, ", "), ")")
 			}
-			str := fmt.Sprintf("directive @%v%v on %v", node.Name, argsStr, join(toSliceString(node.Locations), " | "))
+			repeatable := ""
+			if node.Repeatable {
+				repeatable = " repeatable"
+			}
+			str := fmt.Sprintf("directive @%v%v%v on %v", node.Name, argsStr, repeatable, join(toSliceString(node.Locations), " | "))
 			if desc := getDescription(node); desc != "" {
 				str = fmt.Sprintf("%s\n%s", desc, str)
 			}
@@ -929,7 +937,11 @@ var printDocASTReducer = map[string]visitor.VisitFunc{
 			} else {
 				argsStr = wrap("(", join(args, ", "), ")")
 			}
-			str := fmt.Sprintf("directive @%v%v on %v", name, argsStr, join(locations, " | "))
+			repeatable := ""
+			if isRepeatable, _ := getMapValue(node, "Repeatable").(bool); isRepeatable {
+				repeatable = " repeatable"
+			}
+			str := fmt.Sprintf("directive @%v%v%v on %v", name, argsStr, repeatable, join(locations, " | "))
 			if desc := getDescription(node); desc != "" {
 				str = fmt.Sprintf("%s\n%s", desc, str)
 			}
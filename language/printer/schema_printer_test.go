@@ -80,6 +80,10 @@ interface AnnotatedInterface @onInterface {
   annotatedField(arg: Type @onArg): Type @onField
 }
 
+interface Baz implements Bar & AnnotatedInterface {
+  one: Type
+}
+
 union Feed = Story | Article | Advert
 
 union AnnotatedUnion @onUnion = A | B
@@ -118,6 +122,8 @@ type NoFields {}
 directive @skip(if: Boolean!) on FIELD | FRAGMENT_SPREAD | INLINE_FRAGMENT
 
 directive @include(if: Boolean!) on FIELD | FRAGMENT_SPREAD | INLINE_FRAGMENT
+
+directive @key(fields: String!) repeatable on OBJECT | INTERFACE
 `
 	results := printer.Print(astDoc)
 	if !reflect.DeepEqual(expected, results) {
@@ -113,6 +113,7 @@ var QueryDocumentKeys = KeyMap{
 	},
 	"InterfaceDefinition": []string{
 		"Name",
+		"Interfaces",
 		"Directives",
 		"Fields",
 	},
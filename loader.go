@@ -0,0 +1,197 @@
+package graphql
+
+import (
+	"context"
+	"fmt"
+	"sync"
+)
+
+// BatchResult is the result of loading a single key in a BatchLoadFn.
+type BatchResult struct {
+	Value any
+	Error error
+}
+
+// BatchLoadFn loads a batch of keys at once. It must return exactly one
+// BatchResult per key, in the same order as keys.
+type BatchLoadFn func(ctx context.Context, keys []any) []*BatchResult
+
+// LoaderRegistry holds the request-scoped Loaders of a single execution.
+//
+// The executor installs a registry in the context given to resolvers (see
+// LoaderRegistryFromContext) unless one is already present. While resolving a
+// query, the keys requested from its Loaders are collected across every field
+// of a depth of the result and dispatched to their batch function once, right
+// before the thunks of that depth are called.
+//
+// Example:
+//
+//	"author": &graphql.Field{
+//	  Type: userType,
+//	  Resolve: func(p graphql.ResolveParams) (any, error) {
+//	    loader := graphql.LoaderRegistryFromContext(p.Context).Loader("users", batchLoadUsers)
+//	    return loader.Load(p.Context, p.Source.(*Post).AuthorID), nil
+//	  },
+//	},
+type LoaderRegistry struct {
+	mu      sync.Mutex
+	loaders map[string]*Loader
+	order   []string
+}
+
+// NewLoaderRegistry creates an empty LoaderRegistry.
+func NewLoaderRegistry() *LoaderRegistry {
+	return &LoaderRegistry{
+		loaders: map[string]*Loader{},
+	}
+}
+
+type loaderRegistryKey struct{}
+
+// ContextWithLoaderRegistry returns a copy of ctx carrying the given registry.
+func ContextWithLoaderRegistry(ctx context.Context, registry *LoaderRegistry) context.Context {
+	return context.WithValue(ctx, loaderRegistryKey{}, registry)
+}
+
+// LoaderRegistryFromContext returns the registry carried by ctx, or nil if
+// there is none.
+func LoaderRegistryFromContext(ctx context.Context) *LoaderRegistry {
+	if ctx == nil {
+		return nil
+	}
+	registry, _ := ctx.Value(loaderRegistryKey{}).(*LoaderRegistry)
+	return registry
+}
+
+// Loader returns the Loader registered under name, creating it with the given
+// batch function on first use.
+func (r *LoaderRegistry) Loader(name string, batchFn BatchLoadFn) *Loader {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	if loader, ok := r.loaders[name]; ok {
+		return loader
+	}
+	loader := NewLoader(batchFn)
+	r.loaders[name] = loader
+	r.order = append(r.order, name)
+	return loader
+}
+
+// Dispatch calls the batch function of every Loader which has pending keys.
+func (r *LoaderRegistry) Dispatch(ctx context.Context) {
+	if r == nil {
+		return
+	}
+	r.mu.Lock()
+	loaders := make([]*Loader, 0, len(r.order))
+	for _, name := range r.order {
+		loaders = append(loaders, r.loaders[name])
+	}
+	r.mu.Unlock()
+
+	for _, loader := range loaders {
+		loader.Dispatch(ctx)
+	}
+}
+
+// Loader batches and caches the loading of keys through a BatchLoadFn. Keys
+// must be comparable, as they are used as map keys.
+type Loader struct {
+	batchFn BatchLoadFn
+
+	mu      sync.Mutex
+	pending []*loaderRequest
+	cache   map[any]*loaderRequest
+}
+
+type loaderRequest struct {
+	key    any
+	done   chan struct{}
+	result *BatchResult
+}
+
+// NewLoader creates a Loader for the given batch function.
+func NewLoader(batchFn BatchLoadFn) *Loader {
+	return &Loader{
+		batchFn: batchFn,
+		cache:   map[any]*loaderRequest{},
+	}
+}
+
+// Load queues the given key for the next batch and returns a thunk which
+// returns its value. Returning the thunk from a resolve function lets the
+// executor batch the key with those of sibling fields. Calling the thunk
+// before the batch was dispatched dispatches it immediately.
+func (l *Loader) Load(ctx context.Context, key any) func() (any, error) {
+	l.mu.Lock()
+	req, ok := l.cache[key]
+	if !ok {
+		req = &loaderRequest{
+			key:  key,
+			done: make(chan struct{}),
+		}
+		l.cache[key] = req
+		l.pending = append(l.pending, req)
+	}
+	l.mu.Unlock()
+
+	return func() (any, error) {
+		select {
+		case <-req.done:
+		default:
+			l.Dispatch(ctx)
+			<-req.done
+		}
+		return req.result.Value, req.result.Error
+	}
+}
+
+// Dispatch calls the batch function with the keys loaded since the previous
+// dispatch, if any.
+func (l *Loader) Dispatch(ctx context.Context) {
+	l.mu.Lock()
+	batch := l.pending
+	l.pending = nil
+	l.mu.Unlock()
+
+	if len(batch) == 0 {
+		return
+	}
+
+	keys := make([]any, len(batch))
+	for i, req := range batch {
+		keys[i] = req.key
+	}
+
+	results := l.callBatchFn(ctx, keys)
+	for i, req := range batch {
+		req.result = results[i]
+		if req.result == nil {
+			req.result = &BatchResult{}
+		}
+		close(req.done)
+	}
+}
+
+func (l *Loader) callBatchFn(ctx context.Context, keys []any) (results []*BatchResult) {
+	defer func() {
+		if r := recover(); r != nil {
+			results = batchError(len(keys), fmt.Errorf("batch function panicked: %v", r))
+		}
+	}()
+
+	results = l.batchFn(ctx, keys)
+	if len(results) != len(keys) {
+		return batchError(len(keys), fmt.Errorf(
+			"batch function must return %d results for %d keys, got %d", len(keys), len(keys), len(results)))
+	}
+	return results
+}
+
+func batchError(n int, err error) []*BatchResult {
+	results := make([]*BatchResult, n)
+	for i := range results {
+		results[i] = &BatchResult{Error: err}
+	}
+	return results
+}
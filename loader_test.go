@@ -0,0 +1,149 @@
+package graphql_test
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"reflect"
+	"sync"
+	"testing"
+
+	"github.com/dagger/graphql"
+	"github.com/dagger/graphql/gqlerrors"
+	"github.com/dagger/graphql/language/location"
+	"github.com/dagger/graphql/testutil"
+)
+
+type loaderPost struct {
+	ID       string
+	AuthorID string
+}
+
+func TestLoaderBatchesKeysOfTheSameDepth(t *testing.T) {
+	var mu sync.Mutex
+	var batches [][]any
+	batchLoadUsers := func(ctx context.Context, keys []any) []*graphql.BatchResult {
+		mu.Lock()
+		batches = append(batches, keys)
+		mu.Unlock()
+
+		results := make([]*graphql.BatchResult, len(keys))
+		for i, key := range keys {
+			if key == "unknown" {
+				results[i] = &graphql.BatchResult{Error: fmt.Errorf("user %v not found", key)}
+				continue
+			}
+			results[i] = &graphql.BatchResult{Value: map[string]any{"name": fmt.Sprintf("user %v", key)}}
+		}
+		return results
+	}
+
+	userType := graphql.NewObject(graphql.ObjectConfig{
+		Name: "User",
+		Fields: graphql.Fields{
+			"name": &graphql.Field{Type: graphql.String},
+		},
+	})
+	postType := graphql.NewObject(graphql.ObjectConfig{
+		Name: "Post",
+		Fields: graphql.Fields{
+			"id": &graphql.Field{Type: graphql.String},
+			"author": &graphql.Field{
+				Type: userType,
+				Resolve: func(p graphql.ResolveParams) (any, error) {
+					loader := graphql.LoaderRegistryFromContext(p.Context).Loader("users", batchLoadUsers)
+					return loader.Load(p.Context, p.Source.(*loaderPost).AuthorID), nil
+				},
+			},
+		},
+	})
+	schema, err := graphql.NewSchema(graphql.SchemaConfig{
+		Query: graphql.NewObject(graphql.ObjectConfig{
+			Name: "Query",
+			Fields: graphql.Fields{
+				"posts": &graphql.Field{
+					Type: graphql.NewList(postType),
+					Resolve: func(p graphql.ResolveParams) (any, error) {
+						return []*loaderPost{
+							{ID: "1", AuthorID: "a"},
+							{ID: "2", AuthorID: "b"},
+							{ID: "3", AuthorID: "a"},
+							{ID: "4", AuthorID: "unknown"},
+						}, nil
+					},
+				},
+			},
+		}),
+	})
+	if err != nil {
+		t.Fatalf("Error in schema %v", err.Error())
+	}
+
+	expected := &graphql.Result{
+		Data: map[string]any{
+			"posts": []any{
+				map[string]any{"id": "1", "author": map[string]any{"name": "user a"}},
+				map[string]any{"id": "2", "author": map[string]any{"name": "user b"}},
+				map[string]any{"id": "3", "author": map[string]any{"name": "user a"}},
+				map[string]any{"id": "4", "author": nil},
+			},
+		},
+		Errors: []gqlerrors.FormattedError{
+			{
+				Message:   "user unknown not found",
+				Locations: []location.SourceLocation{{Line: 1, Column: 14}},
+				Path:      []any{"posts", 3, "author"},
+			},
+		},
+	}
+	for _, parallelism := range []int{0, 4} {
+		t.Run(fmt.Sprintf("parallelism %d", parallelism), func(t *testing.T) {
+			batches = nil
+			result := testutil.TestExecute(t, graphql.ExecuteParams{
+				Schema:      schema,
+				AST:         testutil.TestParse(t, `{ posts { id author { name } } }`),
+				Parallelism: parallelism,
+			})
+
+			expectedBatches := [][]any{{"a", "b", "unknown"}}
+			if !reflect.DeepEqual(expectedBatches, batches) {
+				t.Fatalf("Unexpected batches, Diff: %v", testutil.Diff(expectedBatches, batches))
+			}
+			if !testutil.EqualResults(expected, result) {
+				t.Fatalf("Unexpected result, Diff: %v", testutil.Diff(expected, result))
+			}
+		})
+	}
+}
+
+func TestLoaderDispatchesWhenThunkIsCalledEarly(t *testing.T) {
+	calls := 0
+	loader := graphql.NewLoader(func(ctx context.Context, keys []any) []*graphql.BatchResult {
+		calls++
+		return []*graphql.BatchResult{{Value: keys[0]}, {Error: errors.New("boom")}}
+	})
+	ctx := context.Background()
+	a := loader.Load(ctx, "a")
+	b := loader.Load(ctx, "b")
+
+	if value, err := a(); value != "a" || err != nil {
+		t.Fatalf("wrong result, expected a, got %v, %v", value, err)
+	}
+	if _, err := b(); err == nil || err.Error() != "boom" {
+		t.Fatalf("wrong result, expected error boom, got %v", err)
+	}
+	if calls != 1 {
+		t.Fatalf("expected one batch, got %d", calls)
+	}
+}
+
+func TestLoaderReportsMismatchedBatchResults(t *testing.T) {
+	loader := graphql.NewLoader(func(ctx context.Context, keys []any) []*graphql.BatchResult {
+		return nil
+	})
+	_, err := loader.Load(context.Background(), "a")()
+	expected := "batch function must return 1 results for 1 keys, got 0"
+	if err == nil || err.Error() != expected {
+		t.Fatalf("wrong result, expected %q, got %v", expected, err)
+	}
+}
@@ -0,0 +1,165 @@
+package graphql_test
+
+import (
+	"reflect"
+	"strings"
+	"testing"
+
+	"github.com/dagger/graphql"
+	"github.com/dagger/graphql/gqlerrors"
+	"github.com/dagger/graphql/testutil"
+)
+
+const oneOfSDL = `
+input PetInput @oneOf {
+  cat: String
+  dog: String
+}
+
+type Query {
+  pet(input: PetInput): String
+}
+`
+
+func oneOfSchema(t *testing.T) graphql.Schema {
+	schema, err := graphql.BuildSchema(oneOfSDL, graphql.BuildSchemaOptions{
+		Resolvers: map[string]map[string]graphql.FieldResolveFn{
+			"Query": {
+				"pet": func(p graphql.ResolveParams) (any, error) {
+					input, _ := p.Args["input"].(map[string]any)
+					for kind, name := range input {
+						return kind + ":" + name.(string), nil
+					}
+					return nil, nil
+				},
+			},
+		},
+	})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	return schema
+}
+
+func TestOneOf_AcceptsExactlyOneFieldInLiteral(t *testing.T) {
+	result := graphql.Do(graphql.Params{
+		Schema:        oneOfSchema(t),
+		RequestString: `{ pet(input: { cat: "Garfield" }) }`,
+	})
+	expected := &graphql.Result{
+		Data: map[string]any{
+			"pet": "cat:Garfield",
+		},
+	}
+	if !reflect.DeepEqual(expected, result) {
+		t.Fatalf("Unexpected result, Diff: %v", testutil.Diff(expected, result))
+	}
+}
+
+func TestOneOf_RejectsSeveralFieldsInLiteral(t *testing.T) {
+	result := graphql.Do(graphql.Params{
+		Schema:        oneOfSchema(t),
+		RequestString: `{ pet(input: { cat: "Garfield", dog: "Odie" }) }`,
+	})
+	expected := []gqlerrors.FormattedError{
+		testutil.RuleError(`OneOf Input Object "PetInput" must specify exactly one key.`, 1, 14),
+	}
+	if !testutil.EqualFormattedErrors(expected, result.Errors) {
+		t.Fatalf("Unexpected result, Diff: %v", testutil.Diff(expected, result.Errors))
+	}
+}
+
+func TestOneOf_AcceptsExactlyOneFieldInVariable(t *testing.T) {
+	result := graphql.Do(graphql.Params{
+		Schema:        oneOfSchema(t),
+		RequestString: `query ($input: PetInput) { pet(input: $input) }`,
+		VariableValues: map[string]any{
+			"input": map[string]any{"dog": "Odie"},
+		},
+	})
+	expected := &graphql.Result{
+		Data: map[string]any{
+			"pet": "dog:Odie",
+		},
+	}
+	if !reflect.DeepEqual(expected, result) {
+		t.Fatalf("Unexpected result, Diff: %v", testutil.Diff(expected, result))
+	}
+}
+
+func TestOneOf_RejectsInvalidVariables(t *testing.T) {
+	tests := []struct {
+		input   map[string]any
+		message string
+	}{
+		{
+			input:   map[string]any{},
+			message: `Exactly one key must be specified for OneOf type "PetInput".`,
+		},
+		{
+			input:   map[string]any{"cat": "Garfield", "dog": "Odie"},
+			message: `Exactly one key must be specified for OneOf type "PetInput".`,
+		},
+		{
+			input:   map[string]any{"cat": nil},
+			message: `Field "PetInput.cat" must be non-null.`,
+		},
+	}
+	for _, test := range tests {
+		result := graphql.Do(graphql.Params{
+			Schema:        oneOfSchema(t),
+			RequestString: `query ($input: PetInput) { pet(input: $input) }`,
+			VariableValues: map[string]any{
+				"input": test.input,
+			},
+		})
+		if len(result.Errors) != 1 || !strings.HasSuffix(result.Errors[0].Message, test.message) {
+			t.Fatalf("expected error %q for %v, got: %v", test.message, test.input, result.Errors)
+		}
+	}
+}
+
+func TestOneOf_IntrospectsIsOneOf(t *testing.T) {
+	result := graphql.Do(graphql.Params{
+		Schema: oneOfSchema(t),
+		RequestString: `{
+			oneOf: __type(name: "PetInput") { isOneOf }
+			object: __type(name: "Query") { isOneOf }
+		}`,
+	})
+	expected := &graphql.Result{
+		Data: map[string]any{
+			"oneOf":  map[string]any{"isOneOf": true},
+			"object": map[string]any{"isOneOf": nil},
+		},
+	}
+	if !reflect.DeepEqual(expected, result) {
+		t.Fatalf("Unexpected result, Diff: %v", testutil.Diff(expected, result))
+	}
+}
+
+func TestOneOf_PrintsOneOfDirective(t *testing.T) {
+	schema := oneOfSchema(t)
+	printed := graphql.PrintSchema(&schema)
+	expected := strings.TrimPrefix(oneOfSDL, "\n")
+	if printed != expected {
+		t.Fatalf("Unexpected result, Diff: %v", testutil.Diff(expected, printed))
+	}
+}
+
+func TestOneOf_RejectsNonNullFields(t *testing.T) {
+	input := graphql.NewInputObject(graphql.InputObjectConfig{
+		Name:    "PetInput",
+		IsOneOf: true,
+		Fields: graphql.InputObjectConfigFieldMap{
+			"cat": &graphql.InputObjectFieldConfig{
+				Type: graphql.NewNonNull(graphql.String),
+			},
+		},
+	})
+	input.Fields()
+	expected := `OneOf input field PetInput.cat must be nullable.`
+	if err := input.Error(); err == nil || err.Error() != expected {
+		t.Fatalf("expected error %q, got: %v", expected, err)
+	}
+}
@@ -0,0 +1,99 @@
+package graphql
+
+import (
+	"bytes"
+	"encoding/json"
+)
+
+// OrderedMap is a map of response keys to values which remembers the order in
+// which keys were first set. The executor produces it for selection sets when
+// ordered results are requested, so that serializing a Result emits fields in
+// the order they were requested, as required by the spec.
+type OrderedMap struct {
+	keys   []string
+	values map[string]any
+}
+
+// NewOrderedMap creates an empty OrderedMap with room for size keys.
+func NewOrderedMap(size int) *OrderedMap {
+	return &OrderedMap{
+		keys:   make([]string, 0, size),
+		values: make(map[string]any, size),
+	}
+}
+
+// Set sets the value for the given key. Keys keep the position of their first
+// insertion.
+func (m *OrderedMap) Set(key string, value any) {
+	if m.values == nil {
+		m.values = map[string]any{}
+	}
+	if _, ok := m.values[key]; !ok {
+		m.keys = append(m.keys, key)
+	}
+	m.values[key] = value
+}
+
+// Get returns the value stored for the given key, if any.
+func (m *OrderedMap) Get(key string) (any, bool) {
+	value, ok := m.values[key]
+	return value, ok
+}
+
+// Keys returns the keys in insertion order.
+func (m *OrderedMap) Keys() []string {
+	return m.keys
+}
+
+// Len returns the number of keys in the map.
+func (m *OrderedMap) Len() int {
+	return len(m.keys)
+}
+
+// Map returns the contents as a plain map, recursively converting any nested
+// OrderedMaps.
+func (m *OrderedMap) Map() map[string]any {
+	result := make(map[string]any, len(m.keys))
+	for _, key := range m.keys {
+		result[key] = unorderValue(m.values[key])
+	}
+	return result
+}
+
+// MarshalJSON implements json.Marshaler, emitting keys in insertion order.
+func (m *OrderedMap) MarshalJSON() ([]byte, error) {
+	var buf bytes.Buffer
+	buf.WriteByte('{')
+	for i, key := range m.keys {
+		if i > 0 {
+			buf.WriteByte(',')
+		}
+		k, err := json.Marshal(key)
+		if err != nil {
+			return nil, err
+		}
+		buf.Write(k)
+		buf.WriteByte(':')
+		v, err := json.Marshal(m.values[key])
+		if err != nil {
+			return nil, err
+		}
+		buf.Write(v)
+	}
+	buf.WriteByte('}')
+	return buf.Bytes(), nil
+}
+
+func unorderValue(value any) any {
+	switch value := value.(type) {
+	case *OrderedMap:
+		return value.Map()
+	case []any:
+		list := make([]any, len(value))
+		for i, v := range value {
+			list[i] = unorderValue(v)
+		}
+		return list
+	}
+	return value
+}
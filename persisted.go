@@ -0,0 +1,140 @@
+package graphql
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"sync"
+)
+
+// PersistedQueryStore stores the documents of persisted queries by their
+// QueryID, see Params.QueryID.
+type PersistedQueryStore interface {
+	// Get returns the document persisted under the given id, and false if
+	// there is none.
+	Get(ctx context.Context, id string) (string, bool, error)
+
+	// Put persists the given document under the given id.
+	Put(ctx context.Context, id string, query string) error
+}
+
+// PersistedQueryHash returns the id of a persisted query: the hex-encoded
+// SHA-256 hash of its document, as sent by clients of automatic persisted
+// queries in `extensions.persistedQuery.sha256Hash`.
+func PersistedQueryHash(query string) string {
+	hash := sha256.Sum256([]byte(query))
+	return hex.EncodeToString(hash[:])
+}
+
+// InMemoryPersistedQueryStore is a PersistedQueryStore holding documents in
+// memory. It is safe for concurrent use.
+type InMemoryPersistedQueryStore struct {
+	mu      sync.RWMutex
+	queries map[string]string
+}
+
+// NewInMemoryPersistedQueryStore creates an empty InMemoryPersistedQueryStore.
+func NewInMemoryPersistedQueryStore() *InMemoryPersistedQueryStore {
+	return &InMemoryPersistedQueryStore{
+		queries: map[string]string{},
+	}
+}
+
+// Get implements PersistedQueryStore.
+func (s *InMemoryPersistedQueryStore) Get(ctx context.Context, id string) (string, bool, error) {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+	query, ok := s.queries[id]
+	return query, ok, nil
+}
+
+// Put implements PersistedQueryStore.
+func (s *InMemoryPersistedQueryStore) Put(ctx context.Context, id string, query string) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.queries[id] = query
+	return nil
+}
+
+// PersistedQueryNotFoundError is returned when a query is requested by a
+// QueryID which is not in the store. Clients are expected to retry with the
+// full document.
+type PersistedQueryNotFoundError struct {
+	QueryID string
+}
+
+func (e *PersistedQueryNotFoundError) Error() string {
+	return "PersistedQueryNotFound"
+}
+
+// Extensions implements gqlerrors.ExtendedError.
+func (e *PersistedQueryNotFoundError) Extensions() map[string]any {
+	return map[string]any{
+		"code": "PERSISTED_QUERY_NOT_FOUND",
+	}
+}
+
+// PersistedQueryNotSupportedError is returned when a query is requested by a
+// QueryID while no PersistedQueryStore is configured.
+type PersistedQueryNotSupportedError struct{}
+
+func (e *PersistedQueryNotSupportedError) Error() string {
+	return "PersistedQueryNotSupported"
+}
+
+// Extensions implements gqlerrors.ExtendedError.
+func (e *PersistedQueryNotSupportedError) Extensions() map[string]any {
+	return map[string]any{
+		"code": "PERSISTED_QUERY_NOT_SUPPORTED",
+	}
+}
+
+// PersistedQueryHashMismatchError is returned when the document sent along a
+// QueryID does not hash to it.
+type PersistedQueryHashMismatchError struct {
+	QueryID string
+}
+
+func (e *PersistedQueryHashMismatchError) Error() string {
+	return "provided sha does not match query"
+}
+
+// Extensions implements gqlerrors.ExtendedError.
+func (e *PersistedQueryHashMismatchError) Extensions() map[string]any {
+	return map[string]any{
+		"code": "PERSISTED_QUERY_HASH_MISMATCH",
+	}
+}
+
+// resolvePersistedQuery sets the RequestString of a query requested by its
+// QueryID. It reports whether the document is to be persisted once validated.
+func resolvePersistedQuery(p *Params) (persist bool, err error) {
+	if p.QueryID == "" {
+		return false, nil
+	}
+	if p.PersistedQueries == nil {
+		return false, &PersistedQueryNotSupportedError{}
+	}
+	if p.RequestString != "" {
+		if PersistedQueryHash(p.RequestString) != p.QueryID {
+			return false, &PersistedQueryHashMismatchError{QueryID: p.QueryID}
+		}
+		return true, nil
+	}
+	query, ok, err := p.PersistedQueries.Get(persistedQueryContext(p), p.QueryID)
+	if err != nil {
+		return false, err
+	}
+	if !ok {
+		return false, &PersistedQueryNotFoundError{QueryID: p.QueryID}
+	}
+	p.RequestString = query
+	return false, nil
+}
+
+func persistedQueryContext(p *Params) context.Context {
+	if p.Context == nil {
+		return context.Background()
+	}
+	return p.Context
+}
@@ -0,0 +1,134 @@
+package graphql_test
+
+import (
+	"context"
+	"testing"
+
+	"github.com/dagger/graphql"
+	"github.com/dagger/graphql/gqlerrors"
+	"github.com/dagger/graphql/language/location"
+	"github.com/dagger/graphql/testutil"
+)
+
+func TestPersistedQuery_NotFound(t *testing.T) {
+	result := graphql.Do(graphql.Params{
+		Schema:           testutil.StarWarsSchema,
+		QueryID:          graphql.PersistedQueryHash(`{ hero { name } }`),
+		PersistedQueries: graphql.NewInMemoryPersistedQueryStore(),
+	})
+	expected := &graphql.Result{
+		Errors: []gqlerrors.FormattedError{
+			{
+				Message:    "PersistedQueryNotFound",
+				Locations:  []location.SourceLocation{},
+				Extensions: map[string]any{"code": "PERSISTED_QUERY_NOT_FOUND"},
+			},
+		},
+	}
+	if !testutil.EqualResults(expected, result) {
+		t.Fatalf("Unexpected result, Diff: %v", testutil.Diff(expected, result))
+	}
+	if _, ok := result.Errors[0].OriginalError().(*graphql.PersistedQueryNotFoundError); !ok {
+		t.Fatalf("expected a PersistedQueryNotFoundError, got %T", result.Errors[0].OriginalError())
+	}
+}
+
+func TestPersistedQuery_PersistsThenServesByHash(t *testing.T) {
+	store := graphql.NewInMemoryPersistedQueryStore()
+	query := `{ hero { name } }`
+	queryID := graphql.PersistedQueryHash(query)
+	expected := &graphql.Result{
+		Data: map[string]any{
+			"hero": map[string]any{"name": "R2-D2"},
+		},
+	}
+
+	result := graphql.Do(graphql.Params{
+		Schema:           testutil.StarWarsSchema,
+		RequestString:    query,
+		QueryID:          queryID,
+		PersistedQueries: store,
+	})
+	if !testutil.EqualResults(expected, result) {
+		t.Fatalf("Unexpected result, Diff: %v", testutil.Diff(expected, result))
+	}
+	if persisted, ok, _ := store.Get(context.Background(), queryID); !ok || persisted != query {
+		t.Fatalf("expected the document to be persisted, got %q", persisted)
+	}
+
+	result = graphql.Do(graphql.Params{
+		Schema:           testutil.StarWarsSchema,
+		QueryID:          queryID,
+		PersistedQueries: store,
+	})
+	if !testutil.EqualResults(expected, result) {
+		t.Fatalf("Unexpected result, Diff: %v", testutil.Diff(expected, result))
+	}
+}
+
+func TestPersistedQuery_DoesNotPersistInvalidDocuments(t *testing.T) {
+	store := graphql.NewInMemoryPersistedQueryStore()
+	query := `{ hero { unknown } }`
+	queryID := graphql.PersistedQueryHash(query)
+
+	result := graphql.Do(graphql.Params{
+		Schema:           testutil.StarWarsSchema,
+		RequestString:    query,
+		QueryID:          queryID,
+		PersistedQueries: store,
+	})
+	expected := &graphql.Result{
+		Errors: []gqlerrors.FormattedError{
+			{
+				Message:   `Cannot query field "unknown" on type "Character".`,
+				Locations: []location.SourceLocation{{Line: 1, Column: 10}},
+			},
+		},
+	}
+	if !testutil.EqualResults(expected, result) {
+		t.Fatalf("Unexpected result, Diff: %v", testutil.Diff(expected, result))
+	}
+	if _, ok, _ := store.Get(context.Background(), queryID); ok {
+		t.Fatalf("expected invalid document not to be persisted")
+	}
+}
+
+func TestPersistedQuery_RejectsMismatchedHash(t *testing.T) {
+	result := graphql.Do(graphql.Params{
+		Schema:           testutil.StarWarsSchema,
+		RequestString:    `{ hero { name } }`,
+		QueryID:          graphql.PersistedQueryHash(`{ hero { id } }`),
+		PersistedQueries: graphql.NewInMemoryPersistedQueryStore(),
+	})
+	expected := &graphql.Result{
+		Errors: []gqlerrors.FormattedError{
+			{
+				Message:    "provided sha does not match query",
+				Locations:  []location.SourceLocation{},
+				Extensions: map[string]any{"code": "PERSISTED_QUERY_HASH_MISMATCH"},
+			},
+		},
+	}
+	if !testutil.EqualResults(expected, result) {
+		t.Fatalf("Unexpected result, Diff: %v", testutil.Diff(expected, result))
+	}
+}
+
+func TestPersistedQuery_NotSupportedWithoutStore(t *testing.T) {
+	result := graphql.Do(graphql.Params{
+		Schema:  testutil.StarWarsSchema,
+		QueryID: graphql.PersistedQueryHash(`{ hero { name } }`),
+	})
+	expected := &graphql.Result{
+		Errors: []gqlerrors.FormattedError{
+			{
+				Message:    "PersistedQueryNotSupported",
+				Locations:  []location.SourceLocation{},
+				Extensions: map[string]any{"code": "PERSISTED_QUERY_NOT_SUPPORTED"},
+			},
+		},
+	}
+	if !testutil.EqualResults(expected, result) {
+		t.Fatalf("Unexpected result, Diff: %v", testutil.Diff(expected, result))
+	}
+}
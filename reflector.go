@@ -0,0 +1,632 @@
+package graphql
+
+import (
+	"context"
+	"encoding"
+	"fmt"
+	"reflect"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/dagger/graphql/language/ast"
+)
+
+// GraphQLEnum is implemented by the Go types a Reflector maps to enums.
+type GraphQLEnum interface {
+	// GraphQLEnumValues returns the values of the enum by name. The values
+	// must be of the type implementing GraphQLEnum.
+	GraphQLEnumValues() map[string]any
+}
+
+var (
+	contextType         = reflect.TypeOf((*context.Context)(nil)).Elem()
+	errorType           = reflect.TypeOf((*error)(nil)).Elem()
+	enumType            = reflect.TypeOf((*GraphQLEnum)(nil)).Elem()
+	textMarshalerType   = reflect.TypeOf((*encoding.TextMarshaler)(nil)).Elem()
+	textUnmarshalerType = reflect.TypeOf((*encoding.TextUnmarshaler)(nil)).Elem()
+	timeType            = reflect.TypeOf(time.Time{})
+)
+
+// Reflector builds GraphQL types from Go types. Each Go type maps to a single
+// GraphQL type, named after the Go type, which is built once and cached so
+// that types may refer to each other recursively.
+//
+// Go types map to output types as follows:
+//   - strings, bools, integers and floats map to the matching scalars, and
+//     time.Time to DateTime;
+//   - types implementing GraphQLEnum map to enums;
+//   - other types implementing encoding.TextMarshaler map to scalars, which
+//     are input types as well if they implement encoding.TextUnmarshaler;
+//   - slices map to lists and pointers to the type they point to;
+//   - structs map to objects, implementing the interfaces the Reflector
+//     built from the Go interfaces they implement;
+//   - non-empty interfaces map to interfaces, resolved to the object built
+//     from the dynamic type of their values.
+//
+// The fields of objects are the exported fields of the struct, including
+// those of embedded structs, and the methods of the struct or of its pointer
+// which take a context.Context, optionally followed by a struct of arguments,
+// and return a value and an error. Methods of Go interfaces of the same form
+// are the fields of interfaces. Fields and arguments are named following
+// the tag rules of DefaultResolveFn, and methods by their name with its leading
+// capitals lowercased. The `description` and `deprecated` tags of struct fields
+// set the description and deprecation reason of their GraphQL fields, and the
+// "nonnull" option of the `graphql` tag makes them non-null:
+//
+//	type User struct {
+//		ID      string   `graphql:"id,nonnull" description:"The ID of the user."`
+//		Login   string   `deprecated:"Use id."`
+//		Friends []*User
+//	}
+//
+//	func (u *User) Greet(ctx context.Context, args GreetArgs) (string, error)
+//
+// Arguments map to input types the same way, structs mapping to input
+// objects named after the Go type with an "Input" suffix. Unlike fields, they
+// are non-null unless they are pointers, and are decoded into the struct of
+// arguments given to the method.
+//
+// A Reflector is not safe for concurrent use while building types. Failing to
+// map a type leaves the types built before unchanged.
+type Reflector struct {
+	types        map[reflect.Type]Type
+	inputObjects map[reflect.Type]*InputObject
+	names        map[string]reflect.Type
+}
+
+// NewReflector returns a Reflector which has not built any type yet.
+func NewReflector() *Reflector {
+	return &Reflector{
+		types:        map[reflect.Type]Type{},
+		inputObjects: map[reflect.Type]*InputObject{},
+		names:        map[string]reflect.Type{},
+	}
+}
+
+// Object returns the object the Go type of v maps to, typically to be used as
+// a root type of a schema. The methods of root types are called on a zero
+// value of the Go type when no root value is given.
+func (r *Reflector) Object(v any) (*Object, error) {
+	t := reflect.TypeOf(v)
+	if t == nil {
+		return nil, fmt.Errorf("cannot map nil to an object")
+	}
+	ttype, err := r.Output(t)
+	if err != nil {
+		return nil, err
+	}
+	object, ok := ttype.(*Object)
+	if !ok {
+		return nil, fmt.Errorf("%v maps to %v which is not an object", t, ttype)
+	}
+	return object, nil
+}
+
+// Types returns the named types built so far, sorted by name. They are meant
+// to be given as the Types of a SchemaConfig so that the schema includes the
+// objects only reachable through interfaces.
+func (r *Reflector) Types() []Type {
+	types := []Type{}
+	for _, ttype := range r.types {
+		types = append(types, ttype)
+	}
+	for _, ttype := range r.inputObjects {
+		types = append(types, ttype)
+	}
+	sort.Slice(types, func(i, j int) bool {
+		return types[i].Name() < types[j].Name()
+	})
+	return types
+}
+
+// Output returns the nullable output type t maps to.
+func (r *Reflector) Output(t reflect.Type) (ttype Output, err error) {
+	defer r.restoreOnError(r.snapshot(), &err)
+	return r.output(t)
+}
+
+// Input returns the nullable input type t maps to.
+func (r *Reflector) Input(t reflect.Type) (ttype Input, err error) {
+	defer r.restoreOnError(r.snapshot(), &err)
+	return r.input(t)
+}
+
+// Arguments returns the arguments decoded into t, a struct or a pointer to a
+// struct whose exported fields are the arguments.
+func (r *Reflector) Arguments(t reflect.Type) (args FieldConfigArgument, err error) {
+	defer r.restoreOnError(r.snapshot(), &err)
+	return r.arguments(t)
+}
+
+// reflectorSnapshot holds the types a Reflector built at some point
+type reflectorSnapshot struct {
+	types        map[reflect.Type]Type
+	inputObjects map[reflect.Type]*InputObject
+	names        map[string]reflect.Type
+}
+
+func (r *Reflector) snapshot() reflectorSnapshot {
+	snapshot := reflectorSnapshot{
+		types:        make(map[reflect.Type]Type, len(r.types)),
+		inputObjects: make(map[reflect.Type]*InputObject, len(r.inputObjects)),
+		names:        make(map[string]reflect.Type, len(r.names)),
+	}
+	for t, ttype := range r.types {
+		snapshot.types[t] = ttype
+	}
+	for t, ttype := range r.inputObjects {
+		snapshot.inputObjects[t] = ttype
+	}
+	for name, t := range r.names {
+		snapshot.names[name] = t
+	}
+	return snapshot
+}
+
+// restoreOnError restores the given snapshot if *err is set, so that the
+// types partially built before the error are not used afterwards
+func (r *Reflector) restoreOnError(snapshot reflectorSnapshot, err *error) {
+	if *err != nil {
+		r.types = snapshot.types
+		r.inputObjects = snapshot.inputObjects
+		r.names = snapshot.names
+	}
+}
+
+func (r *Reflector) output(t reflect.Type) (Output, error) {
+	for t.Kind() == reflect.Ptr {
+		t = t.Elem()
+	}
+	if ttype, ok := r.types[t]; ok {
+		return ttype.(Output), nil
+	}
+
+	leaf, err := r.leaf(t)
+	if err != nil {
+		return nil, err
+	}
+	if leaf != nil {
+		return leaf.(Output), nil
+	}
+	switch t.Kind() {
+	case reflect.Slice:
+		itemType, err := r.output(t.Elem())
+		if err != nil {
+			return nil, err
+		}
+		return NewList(itemType), nil
+	case reflect.Struct:
+		return r.object(t)
+	case reflect.Interface:
+		if t.NumMethod() > 0 {
+			return r.iface(t)
+		}
+	}
+	return nil, fmt.Errorf("cannot derive an output type from %v", t)
+}
+
+func (r *Reflector) input(t reflect.Type) (Input, error) {
+	for t.Kind() == reflect.Ptr {
+		t = t.Elem()
+	}
+	if ttype, ok := r.inputObjects[t]; ok {
+		return ttype, nil
+	}
+
+	leaf, err := r.leaf(t)
+	if err != nil {
+		return nil, err
+	}
+	if scalar, ok := leaf.(*Scalar); ok && scalar.scalarConfig.ParseValue == nil {
+		return nil, fmt.Errorf("cannot derive an input type from %v, which does not implement encoding.TextUnmarshaler", t)
+	}
+	if leaf != nil {
+		return leaf.(Input), nil
+	}
+	switch t.Kind() {
+	case reflect.Slice:
+		itemType, err := r.argumentType(t.Elem())
+		if err != nil {
+			return nil, err
+		}
+		return NewList(itemType), nil
+	case reflect.Struct:
+		return r.inputObject(t)
+	}
+	return nil, fmt.Errorf("cannot derive an input type from %v", t)
+}
+
+// argumentType returns the input type of the values decoded into t, which
+// is non-null unless t is a pointer.
+func (r *Reflector) argumentType(t reflect.Type) (Input, error) {
+	ttype, err := r.input(t)
+	if err != nil || t.Kind() == reflect.Ptr {
+		return ttype, err
+	}
+	return NewNonNull(ttype), nil
+}
+
+// register reserves the name of the type t maps to
+func (r *Reflector) register(name string, t reflect.Type) error {
+	if other, ok := r.names[name]; ok && other != t {
+		return fmt.Errorf("%v and %v both map to a type named %q", other, t, name)
+	}
+	r.names[name] = t
+	return nil
+}
+
+// leaf returns the scalar or enum t maps to, or nil if it is not a leaf type
+func (r *Reflector) leaf(t reflect.Type) (Leaf, error) {
+	if ttype, ok := r.types[t]; ok {
+		leaf, _ := ttype.(Leaf)
+		return leaf, nil
+	}
+
+	ptrType := reflect.PtrTo(t)
+	switch {
+	case t == timeType:
+		return DateTime, nil
+	case ptrType.Implements(enumType):
+		return r.enum(t)
+	case ptrType.Implements(textMarshalerType):
+		return r.scalar(t)
+	}
+	switch t.Kind() {
+	case reflect.String:
+		return String, nil
+	case reflect.Bool:
+		return Boolean, nil
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64,
+		reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64:
+		return Int, nil
+	case reflect.Float32, reflect.Float64:
+		return Float, nil
+	}
+	return nil, nil
+}
+
+func (r *Reflector) enum(t reflect.Type) (*Enum, error) {
+	if err := r.register(t.Name(), t); err != nil {
+		return nil, err
+	}
+	values := EnumValueConfigMap{}
+	for name, value := range reflect.New(t).Interface().(GraphQLEnum).GraphQLEnumValues() {
+		if reflect.TypeOf(value) != t {
+			return nil, fmt.Errorf("%v: value %v of the enum must be a %v but got: %T", t, name, t, value)
+		}
+		values[name] = &EnumValueConfig{Value: value}
+	}
+	enum := NewEnum(EnumConfig{
+		Name:   t.Name(),
+		Values: values,
+	})
+	if enum.err != nil {
+		return nil, enum.err
+	}
+	r.types[t] = enum
+	return enum, nil
+}
+
+func (r *Reflector) scalar(t reflect.Type) (*Scalar, error) {
+	if err := r.register(t.Name(), t); err != nil {
+		return nil, err
+	}
+	config := ScalarConfig{
+		Name: t.Name(),
+		Serialize: func(value any) (any, error) {
+			v := reflect.ValueOf(value)
+			if v.Kind() == reflect.Ptr && v.IsNil() {
+				return nil, nil
+			}
+			v = reflect.Indirect(v)
+			if v.Type() != t {
+				return nil, fmt.Errorf("expected a %v but got: %T", t, value)
+			}
+			ptr := reflect.New(t)
+			ptr.Elem().Set(v)
+			text, err := ptr.Interface().(encoding.TextMarshaler).MarshalText()
+			if err != nil {
+				return nil, err
+			}
+			return string(text), nil
+		},
+	}
+	if reflect.PtrTo(t).Implements(textUnmarshalerType) {
+		config.ParseValue = func(value any) (any, error) {
+			text, ok := value.(string)
+			if !ok {
+				return nil, fmt.Errorf("expected a string but got: %T", value)
+			}
+			ptr := reflect.New(t)
+			if err := ptr.Interface().(encoding.TextUnmarshaler).UnmarshalText([]byte(text)); err != nil {
+				return nil, err
+			}
+			return ptr.Elem().Interface(), nil
+		}
+		config.ParseLiteral = func(valueAST ast.Value) (any, error) {
+			value, ok := valueAST.(*ast.StringValue)
+			if !ok {
+				return nil, fmt.Errorf("expected a string but got: %v", valueAST.GetKind())
+			}
+			return config.ParseValue(value.Value)
+		}
+	}
+	scalar := NewScalar(config)
+	if scalar.err != nil {
+		return nil, scalar.err
+	}
+	r.types[t] = scalar
+	return scalar, nil
+}
+
+func (r *Reflector) object(t reflect.Type) (*Object, error) {
+	if err := r.register(t.Name(), t); err != nil {
+		return nil, err
+	}
+	var fields Fields
+	object := NewObject(ObjectConfig{
+		Name: t.Name(),
+		Interfaces: InterfacesThunk(func() []*Interface {
+			return r.interfacesOf(t)
+		}),
+		Fields: FieldsThunk(func() Fields {
+			return fields
+		}),
+	})
+	if object.err != nil {
+		return nil, object.err
+	}
+	// cached before building the fields, which may refer to the object
+	r.types[t] = object
+
+	fields = Fields{}
+	for _, structField := range reflect.VisibleFields(t) {
+		if structField.Anonymous {
+			continue
+		}
+		name, ok := argumentName(structField)
+		if !ok {
+			continue
+		}
+		ttype, err := r.output(structField.Type)
+		if err != nil {
+			return nil, fmt.Errorf("%v.%v: %v", t, structField.Name, err)
+		}
+		for _, option := range strings.Split(structField.Tag.Get("graphql"), ",")[1:] {
+			if option == "nonnull" {
+				ttype = NewNonNull(ttype)
+			}
+		}
+		fields[name] = &Field{
+			Name:              name,
+			Type:              ttype,
+			Description:       structField.Tag.Get("description"),
+			DeprecationReason: structField.Tag.Get("deprecated"),
+			Resolve:           structFieldResolver(t, structField.Index),
+		}
+	}
+
+	ptrType := reflect.PtrTo(t)
+	for i := 0; i < ptrType.NumMethod(); i++ {
+		method := ptrType.Method(i)
+		field, err := r.methodField(t, method.Name, method.Type, 1)
+		if err != nil {
+			return nil, err
+		}
+		if field == nil {
+			continue
+		}
+		if _, ok := fields[field.Name]; ok {
+			return nil, fmt.Errorf("%v.%v: field %q is already defined by a struct field", t, method.Name, field.Name)
+		}
+		field.Resolve = methodResolver(t, method)
+		fields[field.Name] = field
+	}
+	return object, nil
+}
+
+// interfacesOf returns the interfaces built from the Go interfaces
+// implemented by t or its pointer
+func (r *Reflector) interfacesOf(t reflect.Type) []*Interface {
+	ptrType := reflect.PtrTo(t)
+	interfaces := []*Interface{}
+	for goType, ttype := range r.types {
+		iface, ok := ttype.(*Interface)
+		if ok && ptrType.Implements(goType) {
+			interfaces = append(interfaces, iface)
+		}
+	}
+	sort.Slice(interfaces, func(i, j int) bool {
+		return interfaces[i].Name() < interfaces[j].Name()
+	})
+	return interfaces
+}
+
+func (r *Reflector) iface(t reflect.Type) (*Interface, error) {
+	if err := r.register(t.Name(), t); err != nil {
+		return nil, err
+	}
+	var fields Fields
+	iface := NewInterface(InterfaceConfig{
+		Name: t.Name(),
+		Fields: FieldsThunk(func() Fields {
+			return fields
+		}),
+		ResolveType: func(p ResolveTypeParams) *Object {
+			t := reflect.TypeOf(p.Value)
+			for t != nil && t.Kind() == reflect.Ptr {
+				t = t.Elem()
+			}
+			object, _ := r.types[t].(*Object)
+			return object
+		},
+	})
+	if iface.err != nil {
+		return nil, iface.err
+	}
+	r.types[t] = iface
+
+	fields = Fields{}
+	for i := 0; i < t.NumMethod(); i++ {
+		method := t.Method(i)
+		field, err := r.methodField(t, method.Name, method.Type, 0)
+		if err != nil {
+			return nil, err
+		}
+		if field != nil {
+			fields[field.Name] = field
+		}
+	}
+	return iface, nil
+}
+
+func (r *Reflector) inputObject(t reflect.Type) (*InputObject, error) {
+	name := t.Name()
+	if name != "" && !strings.HasSuffix(name, "Input") {
+		name += "Input"
+	}
+	if err := r.register(name, t); err != nil {
+		return nil, err
+	}
+	var fields InputObjectConfigFieldMap
+	inputObject := NewInputObject(InputObjectConfig{
+		Name: name,
+		Fields: InputObjectConfigFieldMapThunk(func() InputObjectConfigFieldMap {
+			return fields
+		}),
+	})
+	if inputObject.err != nil {
+		return nil, inputObject.err
+	}
+	r.inputObjects[t] = inputObject
+
+	fields = InputObjectConfigFieldMap{}
+	for i := 0; i < t.NumField(); i++ {
+		structField := t.Field(i)
+		name, ok := argumentName(structField)
+		if !ok {
+			continue
+		}
+		ttype, err := r.argumentType(structField.Type)
+		if err != nil {
+			return nil, fmt.Errorf("%v.%v: %v", t, structField.Name, err)
+		}
+		fields[name] = &InputObjectFieldConfig{
+			Type:        ttype,
+			Description: structField.Tag.Get("description"),
+		}
+	}
+	return inputObject, nil
+}
+
+// methodField returns the field a method of t maps to, or nil if the method
+// is not a resolver. The first skip inputs of methodType are ignored, e.g.
+// the receiver of the method.
+func (r *Reflector) methodField(t reflect.Type, methodName string, methodType reflect.Type, skip int) (*Field, error) {
+	numIn := methodType.NumIn() - skip
+	if numIn < 1 || numIn > 2 || methodType.IsVariadic() || methodType.In(skip) != contextType ||
+		methodType.NumOut() != 2 || methodType.Out(1) != errorType {
+		return nil, nil
+	}
+
+	ttype, err := r.output(methodType.Out(0))
+	if err != nil {
+		return nil, fmt.Errorf("%v.%v: %v", t, methodName, err)
+	}
+	field := &Field{
+		Name: lowerCamel(methodName),
+		Type: ttype,
+		Args: FieldConfigArgument{},
+	}
+	if numIn == 1 {
+		return field, nil
+	}
+
+	field.Args, err = r.arguments(methodType.In(skip + 1))
+	if err != nil {
+		return nil, fmt.Errorf("%v.%v: %v", t, methodName, err)
+	}
+	return field, nil
+}
+
+func (r *Reflector) arguments(t reflect.Type) (FieldConfigArgument, error) {
+	if !isArgsStruct(t) {
+		return nil, fmt.Errorf("arguments must be decoded into a struct but got: %v", t)
+	}
+	if t.Kind() == reflect.Ptr {
+		t = t.Elem()
+	}
+	args := FieldConfigArgument{}
+	for i := 0; i < t.NumField(); i++ {
+		structField := t.Field(i)
+		name, ok := argumentName(structField)
+		if !ok {
+			continue
+		}
+		argType, err := r.argumentType(structField.Type)
+		if err != nil {
+			return nil, fmt.Errorf("%v.%v: %v", t, structField.Name, err)
+		}
+		args = append(args, &ArgumentConfig{
+			Name:        name,
+			Type:        argType,
+			Description: structField.Tag.Get("description"),
+		})
+	}
+	return args, nil
+}
+
+// receiverOf returns a pointer to the value of type t the given source holds
+func receiverOf(source any, t reflect.Type) (reflect.Value, error) {
+	v := reflect.ValueOf(source)
+	switch {
+	case !v.IsValid():
+		// root values default to the zero value
+		return reflect.New(t), nil
+	case v.Type() == t:
+		ptr := reflect.New(t)
+		ptr.Elem().Set(v)
+		return ptr, nil
+	case v.Type() == reflect.PtrTo(t) && !v.IsNil():
+		return v, nil
+	}
+	return reflect.Value{}, fmt.Errorf("expected source of type %v but got: %T", t, source)
+}
+
+func structFieldResolver(t reflect.Type, index []int) FieldResolveFn {
+	return func(p ResolveParams) (any, error) {
+		recv, err := receiverOf(p.Source, t)
+		if err != nil {
+			return nil, err
+		}
+		value, err := recv.Elem().FieldByIndexErr(index)
+		if err != nil {
+			// a nil embedded pointer
+			return nil, nil
+		}
+		return value.Interface(), nil
+	}
+}
+
+func methodResolver(t reflect.Type, method reflect.Method) FieldResolveFn {
+	return func(p ResolveParams) (any, error) {
+		recv, err := receiverOf(p.Source, t)
+		if err != nil {
+			return nil, err
+		}
+		in := []reflect.Value{recv, reflect.ValueOf(p.Context)}
+		if method.Type.NumIn() == 3 {
+			args := reflect.New(method.Type.In(2)).Elem()
+			if err := decodeArgs(p.Args, args); err != nil {
+				return nil, err
+			}
+			in = append(in, args)
+		}
+		out := method.Func.Call(in)
+		if err, _ := out[1].Interface().(error); err != nil {
+			return nil, err
+		}
+		return out[0].Interface(), nil
+	}
+}
@@ -0,0 +1,332 @@
+package graphql_test
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/dagger/graphql"
+	"github.com/dagger/graphql/gqlerrors"
+	"github.com/dagger/graphql/language/location"
+	"github.com/dagger/graphql/testutil"
+)
+
+type Entity interface {
+	ID(ctx context.Context) (string, error)
+}
+
+type Role string
+
+const (
+	RoleAdmin Role = "admin"
+	RoleGuest Role = "guest"
+)
+
+func (Role) GraphQLEnumValues() map[string]any {
+	return map[string]any{
+		"ADMIN": RoleAdmin,
+		"GUEST": RoleGuest,
+	}
+}
+
+type Version struct {
+	Major, Minor int
+}
+
+func (v Version) MarshalText() ([]byte, error) {
+	return []byte(fmt.Sprintf("v%d.%d", v.Major, v.Minor)), nil
+}
+
+func (v *Version) UnmarshalText(text []byte) error {
+	_, err := fmt.Sscanf(string(text), "v%d.%d", &v.Major, &v.Minor)
+	return err
+}
+
+type Timestamps struct {
+	JoinedAt time.Time `graphql:"joinedAt,nonnull"`
+}
+
+type Member struct {
+	Timestamps
+	Login    string `graphql:"login,nonnull" description:"The login of the member."`
+	Nick     string `json:"nickname" deprecated:"Use login."`
+	Role     Role
+	Manager  *Member
+	Reports  []*Member
+	Client   Version
+	password string
+	Ignored  string `graphql:"-"`
+}
+
+func (m *Member) ID(ctx context.Context) (string, error) {
+	return "member:" + m.Login, nil
+}
+
+type GreetArgs struct {
+	Greeting string
+	Name     *string
+}
+
+func (m Member) Greet(ctx context.Context, args GreetArgs) (string, error) {
+	name := m.Login
+	if args.Name != nil {
+		name = *args.Name
+	}
+	return args.Greeting + " " + name, nil
+}
+
+type MemberFilter struct {
+	Role      Role
+	MinClient *Version `json:"minClient"`
+}
+
+type Directory struct {
+	members []*Member
+}
+
+func (d *Directory) Entities(ctx context.Context) ([]Entity, error) {
+	entities := []Entity{}
+	for _, member := range d.members {
+		entities = append(entities, member)
+	}
+	return entities, nil
+}
+
+func (d *Directory) Search(ctx context.Context, args struct{ Filter MemberFilter }) ([]*Member, error) {
+	members := []*Member{}
+	for _, member := range d.members {
+		if member.Role != args.Filter.Role {
+			continue
+		}
+		if min := args.Filter.MinClient; min != nil && member.Client.Major < min.Major {
+			continue
+		}
+		members = append(members, member)
+	}
+	return members, nil
+}
+
+func (d *Directory) Fail(ctx context.Context) (*Member, error) {
+	return nil, fmt.Errorf("directory unavailable")
+}
+
+func checkReflected(t *testing.T, root any, query string, args map[string]any, expected *graphql.Result) {
+	r := graphql.NewReflector()
+	queryType, err := r.Object(&Directory{})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	schema, err := graphql.NewSchema(graphql.SchemaConfig{
+		Query: queryType,
+		Types: r.Types(),
+	})
+	if err != nil {
+		t.Fatalf("Error in schema %v", err.Error())
+	}
+
+	result := testutil.TestExecute(t, graphql.ExecuteParams{
+		Schema: schema,
+		Root:   root,
+		AST:    testutil.TestParse(t, query),
+		Args:   args,
+	})
+	if !testutil.EqualResults(expected, result) {
+		t.Fatalf("Unexpected result, Diff: %v", testutil.Diff(expected, result))
+	}
+}
+
+// reflectedDirectory returns a directory of three members, boss managing
+// alice and bob.
+func reflectedDirectory() *Directory {
+	boss := &Member{Login: "boss", Role: RoleAdmin, Client: Version{2, 1}}
+	alice := &Member{Login: "alice", Nick: "al", Role: RoleGuest, Manager: boss, Client: Version{1, 4}}
+	bob := &Member{Login: "bob", Role: RoleGuest, Manager: boss, Client: Version{2, 0}}
+	boss.Reports = []*Member{alice, bob}
+	boss.JoinedAt = time.Date(2020, 1, 2, 3, 4, 5, 0, time.UTC)
+	return &Directory{members: []*Member{boss, alice, bob}}
+}
+
+func TestReflector_Schema(t *testing.T) {
+	r := graphql.NewReflector()
+	query, err := r.Object(&Directory{})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	schema, err := graphql.NewSchema(graphql.SchemaConfig{
+		Query: query,
+		Types: r.Types(),
+	})
+	if err != nil {
+		t.Fatalf("Error in schema %v", err.Error())
+	}
+	expected := `
+schema {
+  query: Directory
+}
+
+"""
+` + "The `DateTime` scalar type represents a DateTime. The DateTime is serialized as an RFC 3339 quoted string" + `
+"""
+scalar DateTime
+
+type Directory {
+  entities: [Entity]
+  fail: Member
+  search(filter: MemberFilterInput!): [Member]
+}
+
+interface Entity {
+  id: String
+}
+
+type Member implements Entity {
+  client: Version
+  greet(greeting: String!, name: String): String
+  id: String
+  joinedAt: DateTime!
+
+  """The login of the member."""
+  login: String!
+  manager: Member
+  nickname: String @deprecated(reason: "Use login.")
+  reports: [Member]
+  role: Role
+}
+
+input MemberFilterInput {
+  minClient: Version
+  role: Role!
+}
+
+enum Role {
+  ADMIN
+  GUEST
+}
+
+scalar Version
+`
+	if printed := graphql.PrintSchema(&schema); "\n"+printed != expected {
+		t.Fatalf("Unexpected schema, Diff: %v", testutil.Diff(expected, "\n"+printed))
+	}
+}
+
+func TestReflector_ResolvesInterfaces(t *testing.T) {
+	query := `{
+		entities {
+			__typename
+			id
+			... on Member { joinedAt nickname }
+		}
+	}`
+	expected := &graphql.Result{
+		Data: map[string]any{
+			"entities": []any{
+				map[string]any{"__typename": "Member", "id": "member:boss", "joinedAt": "2020-01-02T03:04:05Z", "nickname": ""},
+				map[string]any{"__typename": "Member", "id": "member:alice", "joinedAt": "0001-01-01T00:00:00Z", "nickname": "al"},
+				map[string]any{"__typename": "Member", "id": "member:bob", "joinedAt": "0001-01-01T00:00:00Z", "nickname": ""},
+			},
+		},
+	}
+	checkReflected(t, reflectedDirectory(), query, nil, expected)
+}
+
+func TestReflector_ResolvesMethodsWithArguments(t *testing.T) {
+	query := `query ($min: Version) {
+		search(filter: { role: GUEST, minClient: $min }) {
+			login
+			client
+			role
+			greet(greeting: "hi")
+			manager { greet(greeting: "hello", name: "you") reports { login } }
+		}
+	}`
+	expected := &graphql.Result{
+		Data: map[string]any{
+			"search": []any{
+				map[string]any{
+					"login":  "bob",
+					"client": "v2.0",
+					"role":   "GUEST",
+					"greet":  "hi bob",
+					"manager": map[string]any{
+						"greet": "hello you",
+						"reports": []any{
+							map[string]any{"login": "alice"},
+							map[string]any{"login": "bob"},
+						},
+					},
+				},
+			},
+		},
+	}
+	checkReflected(t, reflectedDirectory(), query, map[string]any{"min": "v2.0"}, expected)
+}
+
+func TestReflector_ReportsMethodErrors(t *testing.T) {
+	expected := &graphql.Result{
+		Data: map[string]any{
+			"fail": nil,
+		},
+		Errors: []gqlerrors.FormattedError{
+			{
+				Message:   "directory unavailable",
+				Locations: []location.SourceLocation{{Line: 1, Column: 3}},
+				Path:      []any{"fail"},
+			},
+		},
+	}
+	checkReflected(t, reflectedDirectory(), `{ fail { login } }`, nil, expected)
+}
+
+func TestReflector_RejectsSourcesOfAnotherType(t *testing.T) {
+	expected := &graphql.Result{
+		Data: map[string]any{
+			"fail": nil,
+		},
+		Errors: []gqlerrors.FormattedError{
+			{
+				Message:   "expected source of type graphql_test.Directory but got: map[string]interface {}",
+				Locations: []location.SourceLocation{{Line: 1, Column: 3}},
+				Path:      []any{"fail"},
+			},
+		},
+	}
+	checkReflected(t, map[string]any{}, `{ fail { login } }`, nil, expected)
+}
+
+func TestReflector_RejectsUnsupportedFields(t *testing.T) {
+	type Settings struct {
+		Values map[string]string
+	}
+	type withMap struct {
+		Settings Settings
+	}
+	r := graphql.NewReflector()
+	_, err := r.Object(withMap{})
+	if err == nil || !strings.Contains(err.Error(), "graphql_test.Settings.Values: cannot derive an output type from map[string]string") {
+		t.Fatalf("expected an error about the map field, got: %v", err)
+	}
+	if types := r.Types(); len(types) != 0 {
+		t.Fatalf("expected the partially built types to be discarded, got: %v", types)
+	}
+}
+
+func TestReflector_RejectsNameConflicts(t *testing.T) {
+	r := graphql.NewReflector()
+	if _, err := r.Object(Member{}); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	types := len(r.Types())
+	type Member struct {
+		Name string
+	}
+	_, err := r.Object(Member{})
+	if err == nil || !strings.Contains(err.Error(), `both map to a type named "Member"`) {
+		t.Fatalf("expected an error about the name conflict, got: %v", err)
+	}
+	if len(r.Types()) != types {
+		t.Fatalf("expected the types to be left unchanged, got: %v", r.Types())
+	}
+}
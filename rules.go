@@ -29,11 +29,13 @@ var SpecifiedRules = []ValidationRuleFn{
 	NoUndefinedVariablesRule,
 	NoUnusedFragmentsRule,
 	NoUnusedVariablesRule,
+	OneOfInputObjectsRule,
 	OverlappingFieldsCanBeMergedRule,
 	PossibleFragmentSpreadsRule,
 	ProvidedNonNullArgumentsRule,
 	ScalarLeafsRule,
 	UniqueArgumentNamesRule,
+	UniqueDirectivesPerLocationRule,
 	UniqueFragmentNamesRule,
 	UniqueInputFieldNamesRule,
 	UniqueOperationNamesRule,
@@ -1429,6 +1431,61 @@ func UniqueArgumentNamesRule(context *ValidationContext) *ValidationRuleInstance
 	}
 }
 
+// UniqueDirectivesPerLocationRule Unique directive names per location
+//
+// A GraphQL document is only valid if all non-repeatable directives at
+// a given location are uniquely named.
+func UniqueDirectivesPerLocationRule(context *ValidationContext) *ValidationRuleInstance {
+	checkDirectives := func(p visitor.VisitFuncParams) (string, any) {
+		var directives []*ast.Directive
+		switch node := p.Node.(type) {
+		case *ast.OperationDefinition:
+			directives = node.Directives
+		case *ast.Field:
+			directives = node.Directives
+		case *ast.FragmentSpread:
+			directives = node.Directives
+		case *ast.InlineFragment:
+			directives = node.Directives
+		case *ast.FragmentDefinition:
+			directives = node.Directives
+		}
+		knownDirectives := map[string]*ast.Directive{}
+		for _, directive := range directives {
+			if directive == nil || directive.Name == nil {
+				continue
+			}
+			directiveName := directive.Name.Value
+			if schemaDirective := context.Schema().Directive(directiveName); schemaDirective != nil && schemaDirective.IsRepeatable {
+				continue
+			}
+			if knownDirective, ok := knownDirectives[directiveName]; ok {
+				reportError(
+					context,
+					fmt.Sprintf(`The directive "@%v" can only be used once at this location.`, directiveName),
+					[]ast.Node{knownDirective, directive},
+				)
+			} else {
+				knownDirectives[directiveName] = directive
+			}
+		}
+		return visitor.ActionNoChange, nil
+	}
+
+	visitorOpts := &visitor.VisitorOptions{
+		KindFuncMap: map[string]visitor.NamedVisitFuncs{
+			kinds.OperationDefinition: {Kind: checkDirectives},
+			kinds.Field:               {Kind: checkDirectives},
+			kinds.FragmentSpread:      {Kind: checkDirectives},
+			kinds.InlineFragment:      {Kind: checkDirectives},
+			kinds.FragmentDefinition:  {Kind: checkDirectives},
+		},
+	}
+	return &ValidationRuleInstance{
+		VisitorOpts: visitorOpts,
+	}
+}
+
 // UniqueFragmentNamesRule Unique fragment names
 //
 // A GraphQL document is only valid if all defined fragments have unique names.
@@ -1469,6 +1526,90 @@ func UniqueFragmentNamesRule(context *ValidationContext) *ValidationRuleInstance
 	}
 }
 
+// OneOfInputObjectsRule OneOf input objects have exactly one field
+//
+// A GraphQL document is only valid if all OneOf input object literals supply
+// exactly one field, and variables supplying that field are non-nullable. As
+// null literals are not part of the grammar, null values for the field of a
+// OneOf input object can only come from variables, whose values are checked
+// when they are coerced.
+func OneOfInputObjectsRule(context *ValidationContext) *ValidationRuleInstance {
+	// variables used as the field of OneOf input object literals, with the
+	// name of the input object
+	oneOfVariables := map[*ast.Variable]string{}
+
+	visitorOpts := &visitor.VisitorOptions{
+		KindFuncMap: map[string]visitor.NamedVisitFuncs{
+			kinds.ObjectValue: {
+				Kind: func(p visitor.VisitFuncParams) (string, any) {
+					node, ok := p.Node.(*ast.ObjectValue)
+					if !ok {
+						return visitor.ActionNoChange, nil
+					}
+					ttype, ok := GetNamed(context.InputType()).(*InputObject)
+					if !ok || !ttype.IsOneOf() {
+						return visitor.ActionNoChange, nil
+					}
+					if len(node.Fields) != 1 {
+						return reportError(
+							context,
+							fmt.Sprintf(`OneOf Input Object "%v" must specify exactly one key.`, ttype.Name()),
+							[]ast.Node{node},
+						)
+					}
+					if variable, ok := node.Fields[0].Value.(*ast.Variable); ok {
+						oneOfVariables[variable] = ttype.Name()
+					}
+					return visitor.ActionNoChange, nil
+				},
+			},
+			kinds.Document: {
+				Leave: func(p visitor.VisitFuncParams) (string, any) {
+					if len(oneOfVariables) == 0 {
+						return visitor.ActionNoChange, nil
+					}
+					for _, definition := range context.Document().Definitions {
+						operation, ok := definition.(*ast.OperationDefinition)
+						if !ok {
+							continue
+						}
+						varDefMap := map[string]*ast.VariableDefinition{}
+						for _, varDef := range operation.VariableDefinitions {
+							if varDef.Variable != nil && varDef.Variable.Name != nil {
+								varDefMap[varDef.Variable.Name.Value] = varDef
+							}
+						}
+						for _, usage := range context.RecursiveVariableUsages(operation) {
+							typeName, ok := oneOfVariables[usage.Node]
+							if !ok || usage.Node.Name == nil {
+								continue
+							}
+							varName := usage.Node.Name.Value
+							varDef, ok := varDefMap[varName]
+							if !ok {
+								continue
+							}
+							if _, ok := varDef.Type.(*ast.NonNull); ok {
+								continue
+							}
+							reportError(
+								context,
+								fmt.Sprintf(`Variable "$%v" must be non-nullable to be used for OneOf Input Object "%v".`,
+									varName, typeName),
+								[]ast.Node{varDef, usage.Node},
+							)
+						}
+					}
+					return visitor.ActionNoChange, nil
+				},
+			},
+		},
+	}
+	return &ValidationRuleInstance{
+		VisitorOpts: visitorOpts,
+	}
+}
+
 // UniqueInputFieldNamesRule Unique input field names
 //
 // A GraphQL input object value is only valid if all supplied fields are
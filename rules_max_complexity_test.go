@@ -0,0 +1,156 @@
+package graphql_test
+
+import (
+	"fmt"
+	"math"
+	"testing"
+
+	"github.com/dagger/graphql"
+	"github.com/dagger/graphql/gqlerrors"
+	"github.com/dagger/graphql/testutil"
+)
+
+func complexitySchema(t *testing.T) *graphql.Schema {
+	var itemType *graphql.Object
+	itemType = graphql.NewObject(graphql.ObjectConfig{
+		Name: "Item",
+		Fields: graphql.FieldsThunk(func() graphql.Fields {
+			return graphql.Fields{
+				"id":   &graphql.Field{Type: graphql.String},
+				"name": &graphql.Field{Type: graphql.String},
+				"children": &graphql.Field{
+					Type: graphql.NewList(itemType),
+					Args: graphql.FieldConfigArgument{
+						{Name: "first", Type: graphql.Int},
+					},
+				},
+			}
+		}),
+	})
+	schema, err := graphql.NewSchema(graphql.SchemaConfig{
+		Query: graphql.NewObject(graphql.ObjectConfig{
+			Name: "Query",
+			Fields: graphql.Fields{
+				"items": &graphql.Field{
+					Type: graphql.NewList(itemType),
+					Args: graphql.FieldConfigArgument{
+						{
+							Name:         "first",
+							Type:         graphql.Int,
+							DefaultValue: 10,
+						},
+					},
+				},
+			},
+		}),
+	})
+	if err != nil {
+		t.Fatalf("Error in schema %v", err.Error())
+	}
+	return &schema
+}
+
+func TestValidate_MaxComplexity_CountsOnePerField(t *testing.T) {
+	testutil.ExpectPassesRule(t, graphql.MaxComplexityRule(3, nil), `
+      {
+        human(id: 4) {
+          name
+          iq
+        }
+      }
+    `)
+}
+func TestValidate_MaxComplexity_RejectsQueriesOverTheLimit(t *testing.T) {
+	testutil.ExpectFailsRule(t, graphql.MaxComplexityRule(3, nil), `
+      query Expensive {
+        human(id: 4) {
+          ...HumanFields
+          relatives {
+            name
+          }
+        }
+      }
+      fragment HumanFields on Human {
+        name
+        iq
+      }
+    `, []gqlerrors.FormattedError{
+		testutil.RuleError(`Operation "Expensive" has a complexity of 5, which exceeds the maximum complexity of 3.`, 2, 7),
+	})
+}
+func TestValidate_MaxComplexity_MultipliesByFirstArgument(t *testing.T) {
+	schema := complexitySchema(t)
+	testutil.ExpectPassesRuleWithSchema(t, schema, graphql.MaxComplexityRule(11, nil), `
+      {
+        items(first: 5) {
+          id
+          name
+        }
+      }
+    `)
+	testutil.ExpectFailsRuleWithSchema(t, schema, graphql.MaxComplexityRule(11, nil), `
+      {
+        items {
+          id
+          name
+        }
+      }
+    `, []gqlerrors.FormattedError{
+		testutil.RuleError(`Operation has a complexity of 21, which exceeds the maximum complexity of 11.`, 2, 7),
+	})
+}
+func TestValidate_MaxComplexity_UsesCustomEstimator(t *testing.T) {
+	estimator := func(p graphql.ComplexityParams) int {
+		if p.Field.Name == "relatives" {
+			return 10 * (1 + p.ChildComplexity)
+		}
+		return 1 + p.ChildComplexity
+	}
+	testutil.ExpectFailsRule(t, graphql.MaxComplexityRule(20, estimator), `
+      {
+        human(id: 4) {
+          relatives {
+            name
+          }
+        }
+      }
+    `, []gqlerrors.FormattedError{
+		testutil.RuleError(`Operation has a complexity of 21, which exceeds the maximum complexity of 20.`, 2, 7),
+	})
+}
+func TestValidate_MaxComplexity_EstimatesVariableArguments(t *testing.T) {
+	schema := complexitySchema(t)
+	testutil.ExpectFailsRuleWithVariables(t, schema, graphql.MaxComplexityRule(100, nil), `
+      query ($n: Int) {
+        items(first: $n) {
+          id
+        }
+      }
+    `, map[string]any{"n": 1000000}, []gqlerrors.FormattedError{
+		testutil.RuleError(`Operation has a complexity of 1000001, which exceeds the maximum complexity of 100.`, 2, 7),
+	})
+	testutil.ExpectFailsRuleWithSchema(t, schema, graphql.MaxComplexityRule(100, nil), `
+      query ($n: Int = 1000) {
+        items(first: $n) {
+          id
+        }
+      }
+    `, []gqlerrors.FormattedError{
+		testutil.RuleError(`Operation has a complexity of 1001, which exceeds the maximum complexity of 100.`, 2, 7),
+	})
+}
+func TestValidate_MaxComplexity_SaturatesInsteadOfOverflowing(t *testing.T) {
+	testutil.ExpectFailsRuleWithSchema(t, complexitySchema(t), graphql.MaxComplexityRule(1000, nil), `
+      {
+        items(first: 2147483647) {
+          children(first: 2147483647) {
+            children(first: 2147483647) {
+              id
+            }
+          }
+        }
+      }
+    `, []gqlerrors.FormattedError{
+		testutil.RuleError(fmt.Sprintf(`Operation has a complexity of %v, which exceeds the maximum complexity of 1000.`, math.MaxInt), 2, 7),
+	})
+}
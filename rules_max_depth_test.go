@@ -0,0 +1,81 @@
+package graphql_test
+
+import (
+	"testing"
+
+	"github.com/dagger/graphql"
+	"github.com/dagger/graphql/gqlerrors"
+	"github.com/dagger/graphql/testutil"
+)
+
+func TestValidate_MaxDepth_AllowsQueriesWithinTheLimit(t *testing.T) {
+	testutil.ExpectPassesRule(t, graphql.MaxDepthRule(3), `
+      {
+        human(id: 4) {
+          relatives {
+            name
+          }
+        }
+      }
+    `)
+}
+func TestValidate_MaxDepth_IgnoresIntrospectionFields(t *testing.T) {
+	testutil.ExpectPassesRule(t, graphql.MaxDepthRule(1), `
+      {
+        __typename
+        __schema {
+          queryType {
+            name
+          }
+        }
+      }
+    `)
+}
+func TestValidate_MaxDepth_RejectsQueriesOverTheLimit(t *testing.T) {
+	testutil.ExpectFailsRule(t, graphql.MaxDepthRule(3), `
+      query Deep {
+        human(id: 4) {
+          relatives {
+            relatives {
+              name
+            }
+          }
+        }
+      }
+    `, []gqlerrors.FormattedError{
+		testutil.RuleError(`Operation "Deep" has a depth of 4, which exceeds the maximum depth of 3.`, 2, 7),
+	})
+}
+func TestValidate_MaxDepth_ExpandsFragments(t *testing.T) {
+	testutil.ExpectFailsRule(t, graphql.MaxDepthRule(3), `
+      {
+        human(id: 4) {
+          ...Relatives
+        }
+      }
+      fragment Relatives on Human {
+        relatives {
+          ... on Human {
+            relatives {
+              name
+            }
+          }
+        }
+      }
+    `, []gqlerrors.FormattedError{
+		testutil.RuleError(`Operation has a depth of 4, which exceeds the maximum depth of 3.`, 2, 7),
+	})
+}
+func TestValidate_MaxDepth_DoesNotLoopOnFragmentCycles(t *testing.T) {
+	testutil.ExpectPassesRule(t, graphql.MaxDepthRule(3), `
+      {
+        human(id: 4) {
+          ...HumanFields
+        }
+      }
+      fragment HumanFields on Human {
+        name
+        ...HumanFields
+      }
+    `)
+}
@@ -0,0 +1,316 @@
+package graphql
+
+import (
+	"fmt"
+	"math"
+	"strings"
+
+	"github.com/dagger/graphql/language/ast"
+	"github.com/dagger/graphql/language/kinds"
+	"github.com/dagger/graphql/language/visitor"
+)
+
+// ComplexityParams Params for ComplexityEstimator()
+type ComplexityParams struct {
+	// Field is the definition of the field being estimated.
+	Field *FieldDefinition
+
+	// Args are the argument values of the field, with defaults applied.
+	// Arguments given as variables hold the values given to
+	// ValidateDocumentWithVariables, else the default value of the variable
+	// or of the argument, if any.
+	Args map[string]any
+
+	// ChildComplexity is the estimated complexity of the field's selection set.
+	ChildComplexity int
+}
+
+// ComplexityEstimator estimates the cost of resolving a single field.
+type ComplexityEstimator func(p ComplexityParams) int
+
+// DefaultComplexityEstimator counts one per field, and multiplies the
+// complexity of the selection set of a field by its `first` or `limit`
+// argument, if it has one. The estimate saturates at math.MaxInt instead of
+// overflowing.
+func DefaultComplexityEstimator(p ComplexityParams) int {
+	multiplier := 1
+	for _, argName := range []string{"first", "limit"} {
+		if n, ok := intArgument(p.Args[argName]); ok && n > 0 {
+			multiplier = n
+			break
+		}
+	}
+	return addComplexity(1, mulComplexity(p.ChildComplexity, multiplier))
+}
+
+// addComplexity adds complexities, saturating at math.MaxInt
+func addComplexity(a, b int) int {
+	if b > 0 && a > math.MaxInt-b {
+		return math.MaxInt
+	}
+	return a + b
+}
+
+// mulComplexity multiplies complexities, saturating at math.MaxInt
+func mulComplexity(a, b int) int {
+	if a > 0 && b > 0 && a > math.MaxInt/b {
+		return math.MaxInt
+	}
+	return a * b
+}
+
+func intArgument(value any) (int, bool) {
+	switch value := value.(type) {
+	case int:
+		return value, true
+	case int32:
+		return int(value), true
+	case int64:
+		return int(value), true
+	}
+	return 0, false
+}
+
+func MaxDepthExceededMessage(opName string, depth int, maxDepth int) string {
+	if opName != "" {
+		return fmt.Sprintf(`Operation "%v" has a depth of %v, which exceeds the maximum depth of %v.`, opName, depth, maxDepth)
+	}
+	return fmt.Sprintf(`Operation has a depth of %v, which exceeds the maximum depth of %v.`, depth, maxDepth)
+}
+
+// MaxDepthRule Maximum query depth
+//
+// A GraphQL document is only valid if the selection sets of its operations
+// are nested at most maxDepth levels deep, fragments included. Introspection
+// fields are not counted.
+func MaxDepthRule(maxDepth int) ValidationRuleFn {
+	return func(context *ValidationContext) *ValidationRuleInstance {
+		visitorOpts := &visitor.VisitorOptions{
+			KindFuncMap: map[string]visitor.NamedVisitFuncs{
+				kinds.OperationDefinition: {
+					Kind: func(p visitor.VisitFuncParams) (string, any) {
+						operation, ok := p.Node.(*ast.OperationDefinition)
+						if !ok || operation == nil {
+							return visitor.ActionSkip, nil
+						}
+						walker := newOperationWalker(context, operation)
+						if depth := walker.depth(operation.SelectionSet, map[string]bool{}); depth > maxDepth {
+							reportError(
+								context,
+								MaxDepthExceededMessage(operationName(operation), depth, maxDepth),
+								[]ast.Node{operation},
+							)
+						}
+						return visitor.ActionSkip, nil
+					},
+				},
+			},
+		}
+		return &ValidationRuleInstance{
+			VisitorOpts: visitorOpts,
+		}
+	}
+}
+
+func MaxComplexityExceededMessage(opName string, complexity int, maxComplexity int) string {
+	if opName != "" {
+		return fmt.Sprintf(`Operation "%v" has a complexity of %v, which exceeds the maximum complexity of %v.`, opName, complexity, maxComplexity)
+	}
+	return fmt.Sprintf(`Operation has a complexity of %v, which exceeds the maximum complexity of %v.`, complexity, maxComplexity)
+}
+
+// MaxComplexityRule Maximum query complexity
+//
+// A GraphQL document is only valid if the estimated complexity of each of its
+// operations is at most maxComplexity. The complexity of a selection set is the
+// sum of the estimates of its fields, fragments included, and every fragment
+// is counted regardless of its type condition. If estimator is nil,
+// DefaultComplexityEstimator is used. Introspection fields are not counted.
+func MaxComplexityRule(maxComplexity int, estimator ComplexityEstimator) ValidationRuleFn {
+	if estimator == nil {
+		estimator = DefaultComplexityEstimator
+	}
+	return func(context *ValidationContext) *ValidationRuleInstance {
+		visitorOpts := &visitor.VisitorOptions{
+			KindFuncMap: map[string]visitor.NamedVisitFuncs{
+				kinds.OperationDefinition: {
+					Kind: func(p visitor.VisitFuncParams) (string, any) {
+						operation, ok := p.Node.(*ast.OperationDefinition)
+						if !ok || operation == nil {
+							return visitor.ActionSkip, nil
+						}
+						walker := newOperationWalker(context, operation)
+						walker.variables = operationVariables(context, operation)
+						rootType := operationRootType(context.Schema(), operation)
+						complexity := walker.complexity(rootType, operation.SelectionSet, estimator, map[string]bool{})
+						if complexity > maxComplexity {
+							reportError(
+								context,
+								MaxComplexityExceededMessage(operationName(operation), complexity, maxComplexity),
+								[]ast.Node{operation},
+							)
+						}
+						return visitor.ActionSkip, nil
+					},
+				},
+			},
+		}
+		return &ValidationRuleInstance{
+			VisitorOpts: visitorOpts,
+		}
+	}
+}
+
+func operationName(operation *ast.OperationDefinition) string {
+	if operation.Name != nil {
+		return operation.Name.Value
+	}
+	return ""
+}
+
+func operationRootType(schema *Schema, operation *ast.OperationDefinition) Composite {
+	var rootType *Object
+	switch operation.Operation {
+	case ast.OperationTypeQuery:
+		rootType = schema.QueryType()
+	case ast.OperationTypeMutation:
+		rootType = schema.MutationType()
+	case ast.OperationTypeSubscription:
+		rootType = schema.SubscriptionType()
+	}
+	if rootType == nil {
+		return nil
+	}
+	return rootType
+}
+
+// operationVariables returns the coerced variable values of the operation,
+// or nil if they are invalid, which is reported when executing it.
+func operationVariables(context *ValidationContext, operation *ast.OperationDefinition) map[string]any {
+	variables, err := getVariableValues(*context.Schema(), operation.VariableDefinitions, context.VariableValues())
+	if err != nil {
+		return nil
+	}
+	return variables
+}
+
+// operationWalker walks the selection sets of an operation, expanding the
+// fragments it references.
+type operationWalker struct {
+	schema    *Schema
+	fragments map[string]*ast.FragmentDefinition
+	variables map[string]any
+}
+
+func newOperationWalker(context *ValidationContext, operation *ast.OperationDefinition) *operationWalker {
+	fragments := map[string]*ast.FragmentDefinition{}
+	for _, fragment := range context.RecursivelyReferencedFragments(operation) {
+		if fragment.Name != nil {
+			fragments[fragment.Name.Value] = fragment
+		}
+	}
+	return &operationWalker{
+		schema:    context.Schema(),
+		fragments: fragments,
+	}
+}
+
+// fragment returns the selection set of a fragment spread, unless the fragment
+// is unknown or already being expanded (cycles are reported by
+// NoFragmentCyclesRule).
+func (w *operationWalker) fragment(spread *ast.FragmentSpread, visiting map[string]bool) (*ast.FragmentDefinition, bool) {
+	if spread.Name == nil || visiting[spread.Name.Value] {
+		return nil, false
+	}
+	fragment, ok := w.fragments[spread.Name.Value]
+	return fragment, ok
+}
+
+func (w *operationWalker) depth(selectionSet *ast.SelectionSet, visiting map[string]bool) int {
+	if selectionSet == nil {
+		return 0
+	}
+	maxDepth := 0
+	for _, selection := range selectionSet.Selections {
+		depth := 0
+		switch selection := selection.(type) {
+		case *ast.Field:
+			if selection.Name == nil || strings.HasPrefix(selection.Name.Value, "__") {
+				continue
+			}
+			depth = 1 + w.depth(selection.SelectionSet, visiting)
+		case *ast.InlineFragment:
+			depth = w.depth(selection.SelectionSet, visiting)
+		case *ast.FragmentSpread:
+			fragment, ok := w.fragment(selection, visiting)
+			if !ok {
+				continue
+			}
+			visiting[fragment.Name.Value] = true
+			depth = w.depth(fragment.SelectionSet, visiting)
+			delete(visiting, fragment.Name.Value)
+		}
+		if depth > maxDepth {
+			maxDepth = depth
+		}
+	}
+	return maxDepth
+}
+
+func (w *operationWalker) complexity(parentType Composite, selectionSet *ast.SelectionSet, estimator ComplexityEstimator, visiting map[string]bool) int {
+	if selectionSet == nil {
+		return 0
+	}
+	total := 0
+	for _, selection := range selectionSet.Selections {
+		switch selection := selection.(type) {
+		case *ast.Field:
+			if selection.Name == nil || strings.HasPrefix(selection.Name.Value, "__") {
+				continue
+			}
+			fieldDef := compositeFieldDef(parentType, selection.Name.Value)
+			if fieldDef == nil {
+				// unknown fields are reported by FieldsOnCorrectTypeRule
+				continue
+			}
+			fieldType, _ := GetNamed(fieldDef.Type).(Composite)
+			total = addComplexity(total, estimator(ComplexityParams{
+				Field:           fieldDef,
+				Args:            getArgumentValues(fieldDef.Args, selection.Arguments, w.variables),
+				ChildComplexity: w.complexity(fieldType, selection.SelectionSet, estimator, visiting),
+			}))
+		case *ast.InlineFragment:
+			total = addComplexity(total, w.complexity(w.typeCondition(parentType, selection.TypeCondition), selection.SelectionSet, estimator, visiting))
+		case *ast.FragmentSpread:
+			fragment, ok := w.fragment(selection, visiting)
+			if !ok {
+				continue
+			}
+			visiting[fragment.Name.Value] = true
+			total = addComplexity(total, w.complexity(w.typeCondition(parentType, fragment.TypeCondition), fragment.SelectionSet, estimator, visiting))
+			delete(visiting, fragment.Name.Value)
+		}
+	}
+	return total
+}
+
+func (w *operationWalker) typeCondition(parentType Composite, typeCondition *ast.Named) Composite {
+	if typeCondition == nil || typeCondition.Name == nil {
+		return parentType
+	}
+	ttype, ok := w.schema.Type(typeCondition.Name.Value).(Composite)
+	if !ok {
+		return nil
+	}
+	return ttype
+}
+
+func compositeFieldDef(parentType Composite, fieldName string) *FieldDefinition {
+	switch parentType := parentType.(type) {
+	case *Object:
+		return parentType.Fields()[fieldName]
+	case *Interface:
+		return parentType.Fields()[fieldName]
+	}
+	return nil
+}
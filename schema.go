@@ -7,6 +7,21 @@ type SchemaConfig struct {
 	Types        []Type
 	Directives   []*Directive
 	Extensions   []Extension
+
+	// DocumentCache caches the documents parsed and validated by Do against
+	// this schema, unless Params.DocumentCache is set.
+	DocumentCache DocumentCache
+
+	// FieldMiddleware wraps the resolver of every field, default resolvers
+	// included. The first middleware is the outermost one, so it runs first
+	// and sees the final result last. Middleware may change the params passed
+	// to next, or return without calling it.
+	FieldMiddleware []func(next FieldResolveFn) FieldResolveFn
+
+	// LenientSerialization makes fields whose value fails to be serialized by
+	// their Scalar or Enum type resolve to null, as they used to, instead of
+	// failing with a SerializationError.
+	LenientSerialization bool
 }
 
 type TypeMap map[string]Type
@@ -37,18 +52,30 @@ type Schema struct {
 	typeMap    TypeMap
 	directives []*Directive
 
-	queryType        *Object
-	mutationType     *Object
-	subscriptionType *Object
-	implementations  map[string][]*Object
-	possibleTypeMap  map[string]map[string]bool
-	extensions       []Extension
+	queryType            *Object
+	mutationType         *Object
+	subscriptionType     *Object
+	implementations      map[string][]*Object
+	possibleTypeMap      map[string]map[string]bool
+	extensions           []Extension
+	documentCache        DocumentCache
+	fieldMiddleware      []func(next FieldResolveFn) FieldResolveFn
+	lenientSerialization bool
+
+	// id identifies the schema in document cache keys, and changes whenever
+	// types are appended.
+	id uint64
 }
 
 func NewSchema(config SchemaConfig) (Schema, error) {
 	var err error
 
-	schema := Schema{}
+	schema := Schema{
+		id:                   nextSchemaID(),
+		documentCache:        config.DocumentCache,
+		fieldMiddleware:      config.FieldMiddleware,
+		lenientSerialization: config.LenientSerialization,
+	}
 
 	if err = invariant(config.Query != nil, "Schema query must be Object Type but got: nil."); err != nil {
 		return schema, err
@@ -111,37 +138,18 @@ func NewSchema(config SchemaConfig) (Schema, error) {
 	schema.typeMap = typeMap
 
 	// Keep track of all implementations by interface name.
-	if schema.implementations == nil {
-		schema.implementations = map[string][]*Object{}
-	}
-	for _, ttype := range schema.typeMap {
-		if ttype, ok := ttype.(*Object); ok {
-			for _, iface := range ttype.Interfaces() {
-				impls, ok := schema.implementations[iface.Name()]
-				if impls == nil || !ok {
-					impls = []*Object{}
-				}
-				impls = append(impls, ttype)
-				schema.implementations[iface.Name()] = impls
-			}
-		}
-	}
+	schema.collectImplementations()
 
 	// Enforce correct interface implementations
-	for _, ttype := range schema.typeMap {
-		if ttype, ok := ttype.(*Object); ok {
-			for _, iface := range ttype.Interfaces() {
-				err := assertObjectImplementsInterface(&schema, ttype, iface)
-				if err != nil {
-					return schema, err
-				}
-			}
-		}
+	if err = schema.assertImplementations(); err != nil {
+		return schema, err
 	}
 
+	schema.buildPossibleTypeMap()
+
 	// Add extensions from config
-	if len(config.Extensions) != 0 {
-		schema.extensions = config.Extensions
+	if err = schema.AddExtensions(config.Extensions...); err != nil {
+		return schema, err
 	}
 
 	return schema, nil
@@ -152,34 +160,14 @@ func NewSchema(config SchemaConfig) (Schema, error) {
 func (gq *Schema) AddImplementation() error {
 
 	// Keep track of all implementations by interface name.
-	if gq.implementations == nil {
-		gq.implementations = map[string][]*Object{}
-	}
-	for _, ttype := range gq.typeMap {
-		if ttype, ok := ttype.(*Object); ok {
-			for _, iface := range ttype.Interfaces() {
-				impls, ok := gq.implementations[iface.Name()]
-				if impls == nil || !ok {
-					impls = []*Object{}
-				}
-				impls = append(impls, ttype)
-				gq.implementations[iface.Name()] = impls
-			}
-		}
-	}
+	gq.collectImplementations()
 
 	// Enforce correct interface implementations
-	for _, ttype := range gq.typeMap {
-		if ttype, ok := ttype.(*Object); ok {
-			for _, iface := range ttype.Interfaces() {
-				err := assertObjectImplementsInterface(gq, ttype, iface)
-				if err != nil {
-					return err
-				}
-			}
-		}
+	if err := gq.assertImplementations(); err != nil {
+		return err
 	}
 
+	gq.buildPossibleTypeMap()
 	return nil
 }
 
@@ -194,6 +182,8 @@ func (gq *Schema) AppendType(objectType Type) error {
 	if err != nil {
 		return err
 	}
+	// documents validated against the previous types may no longer be valid
+	gq.id = nextSchemaID()
 	//Now Add interface implementation..
 	return gq.AddImplementation()
 }
@@ -243,30 +233,118 @@ func (gq *Schema) PossibleTypes(abstractType Abstract) []*Object {
 	return []*Object{}
 }
 func (gq *Schema) IsPossibleType(abstractType Abstract, possibleType *Object) bool {
-	possibleTypeMap := gq.possibleTypeMap
-	if possibleTypeMap == nil {
-		possibleTypeMap = map[string]map[string]bool{}
+	if typeMap, ok := gq.possibleTypeMap[abstractType.Name()]; ok {
+		return typeMap[possibleType.Name()]
 	}
+	// abstract type outside of the type map, look it up without caching
+	for _, ttype := range gq.PossibleTypes(abstractType) {
+		if ttype.Name() == possibleType.Name() {
+			return true
+		}
+	}
+	return false
+}
 
-	if typeMap, ok := possibleTypeMap[abstractType.Name()]; !ok {
-		typeMap = map[string]bool{}
+// buildPossibleTypeMap precomputes the possible types of every abstract type
+// in the type map, so that IsPossibleType can be called concurrently during
+// execution.
+func (gq *Schema) buildPossibleTypeMap() {
+	possibleTypeMap := map[string]map[string]bool{}
+	for _, ttype := range gq.typeMap {
+		if !IsAbstractType(ttype) {
+			continue
+		}
+		abstractType := ttype.(Abstract)
+		typeMap := map[string]bool{}
 		for _, possibleType := range gq.PossibleTypes(abstractType) {
 			typeMap[possibleType.Name()] = true
 		}
 		possibleTypeMap[abstractType.Name()] = typeMap
 	}
-
 	gq.possibleTypeMap = possibleTypeMap
-	if typeMap, ok := possibleTypeMap[abstractType.Name()]; ok {
-		isPossible, _ := typeMap[possibleType.Name()]
-		return isPossible
+}
+
+// collectImplementations records the object types implementing each
+// interface, either directly or through the interfaces they implement.
+func (gq *Schema) collectImplementations() {
+	implementations := map[string][]*Object{}
+	for _, ttype := range gq.typeMap {
+		object, ok := ttype.(*Object)
+		if !ok {
+			continue
+		}
+		seen := map[string]bool{}
+		var collect func(interfaces []*Interface)
+		collect = func(interfaces []*Interface) {
+			for _, iface := range interfaces {
+				if seen[iface.Name()] {
+					continue
+				}
+				seen[iface.Name()] = true
+				implementations[iface.Name()] = append(implementations[iface.Name()], object)
+				collect(iface.Interfaces())
+			}
+		}
+		collect(object.Interfaces())
+	}
+	gq.implementations = implementations
+}
+
+// implementingType is a type which may implement interfaces.
+type implementingType interface {
+	Named
+	Error() error
+	Fields() FieldDefinitionMap
+	Interfaces() []*Interface
+}
+
+// assertImplementations enforces that every object and interface type
+// correctly implements its interfaces.
+func (gq *Schema) assertImplementations() error {
+	for _, ttype := range gq.typeMap {
+		implementing, ok := ttype.(implementingType)
+		if !ok {
+			continue
+		}
+		for _, iface := range implementing.Interfaces() {
+			if err := assertImplementsInterface(gq, implementing, iface); err != nil {
+				return err
+			}
+		}
+	}
+	return nil
+}
+
+// implementsInterface reports whether a type declares the given interface.
+func implementsInterface(ttype implementingType, iface *Interface) bool {
+	for _, implemented := range ttype.Interfaces() {
+		if implemented == iface {
+			return true
+		}
 	}
 	return false
 }
 
-// AddExtensions can be used to add additional extensions to the schema
-func (gq *Schema) AddExtensions(e ...Extension) {
+// AddExtensions can be used to add additional extensions to the schema.
+// Extensions are run in the order they were added, and their finish functions
+// in the reverse order. Extension names must be unique, if any of the given
+// extensions is named like another one none of them are added.
+func (gq *Schema) AddExtensions(e ...Extension) error {
+	names := map[string]bool{}
+	for _, ext := range gq.extensions {
+		names[ext.Name()] = true
+	}
+	for _, ext := range e {
+		err := invariantf(
+			!names[ext.Name()],
+			`Schema must contain unique named extensions but contains multiple extensions named "%v".`, ext.Name())
+		if err != nil {
+			return err
+		}
+		names[ext.Name()] = true
+	}
 	gq.extensions = append(gq.extensions, e...)
+	return nil
 }
 
 // map-reduce
@@ -315,10 +393,11 @@ func typeMapReducer(schema *Schema, typeMap TypeMap, objectType Type) (TypeMap,
 				return typeMap, err
 			}
 		}
-	case *Object:
+	}
+	if objectType, ok := objectType.(implementingType); ok {
 		interfaces := objectType.Interfaces()
-		if objectType.err != nil {
-			return typeMap, objectType.err
+		if objectType.Error() != nil {
+			return typeMap, objectType.Error()
 		}
 		for _, innerObjectType := range interfaces {
 			if innerObjectType.err != nil {
@@ -380,7 +459,28 @@ func typeMapReducer(schema *Schema, typeMap TypeMap, objectType Type) (TypeMap,
 	return typeMap, nil
 }
 
-func assertObjectImplementsInterface(schema *Schema, object *Object, iface *Interface) error {
+func assertImplementsInterface(schema *Schema, object implementingType, iface *Interface) error {
+	// Assert the interfaces implemented by the interface are also implemented,
+	// which also rules out cycles.
+	for _, transitive := range iface.Interfaces() {
+		err := invariantf(
+			Named(transitive) != object,
+			`Type %v cannot implement %v because it would create a circular reference.`,
+			object, iface,
+		)
+		if err != nil {
+			return err
+		}
+		err = invariantf(
+			implementsInterface(object, transitive),
+			`Type %v must implement %v because it is implemented by %v.`,
+			object, transitive, iface,
+		)
+		if err != nil {
+			return err
+		}
+	}
+
 	objectFieldMap := object.Fields()
 	ifaceFieldMap := iface.Fields()
 
@@ -535,6 +635,9 @@ func isTypeSubTypeOf(schema *Schema, maybeSubType Type, superType Type) bool {
 		if maybeSubType, ok := maybeSubType.(*Object); ok && schema.IsPossibleType(superType, maybeSubType) {
 			return true
 		}
+		if maybeSubType, ok := maybeSubType.(*Interface); ok && implementsInterface(maybeSubType, superType) {
+			return true
+		}
 	}
 	if superType, ok := superType.(*Union); ok {
 		if maybeSubType, ok := maybeSubType.(*Object); ok && schema.IsPossibleType(superType, maybeSubType) {
@@ -0,0 +1,316 @@
+package graphql
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+
+	"github.com/dagger/graphql/language/printer"
+)
+
+// PrintSchema prints the types and directives of a schema as SDL, leaving out
+// the introspection types and the specified scalars and directives. Types,
+// fields, input fields and enum values are sorted by name, so that the output
+// is stable.
+func PrintSchema(schema *Schema) string {
+	return printFilteredSchema(schema, func(directive *Directive) bool {
+		return !isSpecifiedDirective(directive)
+	}, isDefinedType)
+}
+
+// PrintIntrospectionSchema prints the introspection types and the specified
+// directives of a schema as SDL.
+func PrintIntrospectionSchema(schema *Schema) string {
+	return printFilteredSchema(schema, isSpecifiedDirective, isIntrospectionType)
+}
+
+func isSpecifiedDirective(directive *Directive) bool {
+	return isSpecifiedDirectiveName(directive.Name)
+}
+
+func isIntrospectionType(ttype Type) bool {
+	return strings.HasPrefix(ttype.Name(), "__")
+}
+
+func isDefinedType(ttype Type) bool {
+	if isIntrospectionType(ttype) {
+		return false
+	}
+	_, ok := specifiedScalars[ttype.Name()]
+	return !ok
+}
+
+func printFilteredSchema(schema *Schema, directiveFilter func(*Directive) bool, typeFilter func(Type) bool) string {
+	defs := []string{}
+	if def := printSchemaDefinition(schema); def != "" && typeFilter(schema.QueryType()) {
+		defs = append(defs, def)
+	}
+	for _, directive := range schema.Directives() {
+		if directiveFilter(directive) {
+			defs = append(defs, printDirective(schema, directive))
+		}
+	}
+
+	typeNames := []string{}
+	for name, ttype := range schema.TypeMap() {
+		if typeFilter(ttype) {
+			typeNames = append(typeNames, name)
+		}
+	}
+	sort.Strings(typeNames)
+	for _, name := range typeNames {
+		defs = append(defs, printType(schema, schema.Type(name)))
+	}
+	return strings.Join(defs, "\n\n") + "\n"
+}
+
+// printSchemaDefinition prints the schema definition, unless its operation
+// types have the default names, in which case it can be omitted.
+func printSchemaDefinition(schema *Schema) string {
+	queryType := schema.QueryType()
+	mutationType := schema.MutationType()
+	subscriptionType := schema.SubscriptionType()
+	if queryType.Name() == "Query" &&
+		(mutationType == nil || mutationType.Name() == "Mutation") &&
+		(subscriptionType == nil || subscriptionType.Name() == "Subscription") {
+		return ""
+	}
+
+	operationTypes := []string{fmt.Sprintf("  query: %v", queryType.Name())}
+	if mutationType != nil {
+		operationTypes = append(operationTypes, fmt.Sprintf("  mutation: %v", mutationType.Name()))
+	}
+	if subscriptionType != nil {
+		operationTypes = append(operationTypes, fmt.Sprintf("  subscription: %v", subscriptionType.Name()))
+	}
+	return fmt.Sprintf("schema {\n%v\n}", strings.Join(operationTypes, "\n"))
+}
+
+func printType(schema *Schema, ttype Type) string {
+	switch ttype := ttype.(type) {
+	case *Scalar:
+		return printDescription(ttype.Description(), "", true) + "scalar " + ttype.Name()
+	case *Object:
+		return printDescription(ttype.Description(), "", true) +
+			"type " + ttype.Name() + printImplementedInterfaces(ttype.Interfaces()) +
+			printAppliedDirectives(schema, ttype.AppliedDirectives()) +
+			printFields(schema, ttype.Fields())
+	case *Interface:
+		return printDescription(ttype.Description(), "", true) +
+			"interface " + ttype.Name() + printImplementedInterfaces(ttype.Interfaces()) +
+			printFields(schema, ttype.Fields())
+	case *Union:
+		types := []string{}
+		for _, member := range ttype.Types() {
+			types = append(types, member.Name())
+		}
+		possibleTypes := ""
+		if len(types) > 0 {
+			possibleTypes = " = " + strings.Join(types, " | ")
+		}
+		return printDescription(ttype.Description(), "", true) + "union " + ttype.Name() + possibleTypes
+	case *Enum:
+		values := append([]*EnumValueDefinition{}, ttype.Values()...)
+		sort.Slice(values, func(i, j int) bool {
+			return values[i].Name < values[j].Name
+		})
+		lines := []string{}
+		for i, value := range values {
+			lines = append(lines, printDescription(value.Description, "  ", i == 0)+
+				"  "+value.Name+printDeprecated(value.DeprecationReason)+
+				printAppliedDirectives(schema, value.AppliedDirectives))
+		}
+		return printDescription(ttype.Description(), "", true) +
+			"enum " + ttype.Name() + printBlock(lines)
+	case *InputObject:
+		fields := ttype.Fields()
+		names := make([]string, 0, len(fields))
+		for name := range fields {
+			names = append(names, name)
+		}
+		sort.Strings(names)
+		lines := []string{}
+		for i, name := range names {
+			field := fields[name]
+			lines = append(lines, printDescription(field.Description(), "  ", i == 0)+
+				"  "+printInputValue(field.Name(), field.Type, field.DefaultValue)+
+				printAppliedDirectives(schema, field.AppliedDirectives))
+		}
+		oneOf := ""
+		if ttype.IsOneOf() {
+			oneOf = " @oneOf"
+		}
+		return printDescription(ttype.Description(), "", true) +
+			"input " + ttype.Name() + oneOf + printBlock(lines)
+	}
+	return ""
+}
+
+func printImplementedInterfaces(interfaces []*Interface) string {
+	if len(interfaces) == 0 {
+		return ""
+	}
+	names := []string{}
+	for _, iface := range interfaces {
+		names = append(names, iface.Name())
+	}
+	return " implements " + strings.Join(names, " & ")
+}
+
+func printFields(schema *Schema, fields FieldDefinitionMap) string {
+	names := make([]string, 0, len(fields))
+	for name := range fields {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	lines := []string{}
+	for i, name := range names {
+		field := fields[name]
+		lines = append(lines, printDescription(field.Description, "  ", i == 0)+
+			"  "+field.Name+printArgs(schema, field.Args, "  ")+": "+field.Type.String()+
+			printDeprecated(field.DeprecationReason)+
+			printAppliedDirectives(schema, field.AppliedDirectives))
+	}
+	return printBlock(lines)
+}
+
+func printBlock(lines []string) string {
+	if len(lines) == 0 {
+		return ""
+	}
+	return " {\n" + strings.Join(lines, "\n") + "\n}"
+}
+
+// printArgs prints arguments on a single line, unless some of them have a
+// description, in which case each is printed on its own line.
+func printArgs(schema *Schema, args []*Argument, indentation string) string {
+	if len(args) == 0 {
+		return ""
+	}
+	described := false
+	for _, arg := range args {
+		if arg.Description() != "" {
+			described = true
+			break
+		}
+	}
+	if !described {
+		printed := []string{}
+		for _, arg := range args {
+			printed = append(printed, printInputValue(arg.Name(), arg.Type, arg.DefaultValue)+
+				printAppliedDirectives(schema, arg.AppliedDirectives))
+		}
+		return "(" + strings.Join(printed, ", ") + ")"
+	}
+
+	lines := []string{}
+	for i, arg := range args {
+		lines = append(lines, printDescription(arg.Description(), "  "+indentation, i == 0)+
+			"  "+indentation+printInputValue(arg.Name(), arg.Type, arg.DefaultValue)+
+			printAppliedDirectives(schema, arg.AppliedDirectives))
+	}
+	return "(\n" + strings.Join(lines, "\n") + "\n" + indentation + ")"
+}
+
+func printInputValue(name string, ttype Input, defaultValue any) string {
+	printed := name + ": " + ttype.String()
+	if defaultValue != nil && !isNullish(defaultValue) {
+		if valueAST := astFromValue(defaultValue, ttype); valueAST != nil {
+			printed += fmt.Sprintf(" = %v", printer.Print(valueAST))
+		}
+	}
+	return printed
+}
+
+func printDirective(schema *Schema, directive *Directive) string {
+	repeatable := ""
+	if directive.IsRepeatable {
+		repeatable = " repeatable"
+	}
+	return printDescription(directive.Description, "", true) +
+		"directive @" + directive.Name + printArgs(schema, directive.Args, "") + repeatable +
+		" on " + strings.Join(directive.Locations, " | ")
+}
+
+// printAppliedDirectives prints directives applied to an element of the
+// schema, with their arguments sorted by name. Arguments are printed according
+// to the definition of the directive in the schema, if any.
+func printAppliedDirectives(schema *Schema, directives []*AppliedDirective) string {
+	printed := ""
+	for _, directive := range directives {
+		printed += " @" + directive.Name
+		if len(directive.Args) == 0 {
+			continue
+		}
+		argTypes := map[string]Input{}
+		if definition := schema.Directive(directive.Name); definition != nil {
+			for _, arg := range definition.Args {
+				argTypes[arg.Name()] = arg.Type
+			}
+		}
+		names := make([]string, 0, len(directive.Args))
+		for name := range directive.Args {
+			names = append(names, name)
+		}
+		sort.Strings(names)
+		args := []string{}
+		for _, name := range names {
+			var argType Type
+			if ttype, ok := argTypes[name]; ok {
+				argType = ttype
+			}
+			if valueAST := astFromValue(directive.Args[name], argType); valueAST != nil {
+				args = append(args, fmt.Sprintf("%v: %v", name, printer.Print(valueAST)))
+			}
+		}
+		if len(args) > 0 {
+			printed += "(" + strings.Join(args, ", ") + ")"
+		}
+	}
+	return printed
+}
+
+func printDeprecated(reason string) string {
+	if reason == "" {
+		return ""
+	}
+	if reason == DefaultDeprecationReason {
+		return " @deprecated"
+	}
+	return fmt.Sprintf(" @deprecated(reason: %v)", printer.Print(astFromValue(reason, String)))
+}
+
+// printDescription prints a description as a block string, on its own lines
+// at the given indentation. Descriptions of the fields of a block, but the
+// first, are preceded by an empty line.
+func printDescription(description string, indentation string, firstInBlock bool) string {
+	if description == "" {
+		return ""
+	}
+	blockString := printBlockString(description, len(description) > 70)
+	prefix := indentation
+	if indentation != "" && !firstInBlock {
+		prefix = "\n" + indentation
+	}
+	return prefix + strings.ReplaceAll(blockString, "\n", "\n"+indentation) + "\n"
+}
+
+// printBlockString prints a string as a block string, on a single line if it
+// can and multipleLines is false.
+func printBlockString(value string, multipleLines bool) string {
+	singleLine := !strings.Contains(value, "\n")
+	leadingSpace := strings.HasPrefix(value, " ") || strings.HasPrefix(value, "\t")
+	trailingQuote := strings.HasSuffix(value, `"`)
+	trailingBackslash := strings.HasSuffix(value, `\`)
+	multipleLines = multipleLines || !singleLine || trailingQuote || trailingBackslash
+
+	result := ""
+	if multipleLines && !(singleLine && leadingSpace) {
+		result += "\n"
+	}
+	result += value
+	if multipleLines {
+		result += "\n"
+	}
+	return `"""` + strings.ReplaceAll(result, `"""`, `\"""`) + `"""`
+}
@@ -0,0 +1,197 @@
+package graphql_test
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/dagger/graphql"
+	"github.com/dagger/graphql/testutil"
+)
+
+func TestPrintSchema_PrintsTypesBuiltInGo(t *testing.T) {
+	colorType := graphql.NewEnum(graphql.EnumConfig{
+		Name: "Color",
+		Values: graphql.EnumValueConfigMap{
+			"RED":   &graphql.EnumValueConfig{Value: 0},
+			"GREEN": &graphql.EnumValueConfig{Value: 1, Description: "The color of grass."},
+			"BLUE":  &graphql.EnumValueConfig{Value: 2, DeprecationReason: graphql.DefaultDeprecationReason},
+		},
+	})
+	filterType := graphql.NewInputObject(graphql.InputObjectConfig{
+		Name: "Filter",
+		Fields: graphql.InputObjectConfigFieldMap{
+			"color": &graphql.InputObjectFieldConfig{Type: colorType, DefaultValue: 0},
+			"limit": &graphql.InputObjectFieldConfig{Type: graphql.Int, DefaultValue: 10},
+		},
+	})
+	namedType := graphql.NewInterface(graphql.InterfaceConfig{
+		Name: "Named",
+		Fields: graphql.Fields{
+			"name": &graphql.Field{Type: graphql.String},
+		},
+	})
+	fruitType := graphql.NewObject(graphql.ObjectConfig{
+		Name:        "Fruit",
+		Description: "A fruit.\n\nIt grows on trees.",
+		Interfaces:  []*graphql.Interface{namedType},
+		Fields: graphql.Fields{
+			"name":  &graphql.Field{Type: graphql.String},
+			"color": &graphql.Field{Type: graphql.NewNonNull(colorType), DeprecationReason: `Use "colors".`},
+		},
+	})
+	schema, err := graphql.NewSchema(graphql.SchemaConfig{
+		Query: graphql.NewObject(graphql.ObjectConfig{
+			Name: "Query",
+			Fields: graphql.Fields{
+				"fruits": &graphql.Field{
+					Type: graphql.NewList(fruitType),
+					Args: graphql.FieldConfigArgument{
+						{Name: "filter", Type: filterType, DefaultValue: map[string]any{"color": 2, "limit": 5}},
+						{Name: "first", Type: graphql.Int, Description: "Returns the first n fruits."},
+					},
+				},
+			},
+		}),
+		Directives: append(graphql.SpecifiedDirectives, graphql.NewDirective(graphql.DirectiveConfig{
+			Name:        "cached",
+			Description: "Caches the result of a field.",
+			Locations:   []string{graphql.DirectiveLocationField, graphql.DirectiveLocationFragmentSpread},
+			Args: graphql.FieldConfigArgument{
+				{Name: "ttl", Type: graphql.Int, DefaultValue: 60},
+			},
+		})),
+	})
+	if err != nil {
+		t.Fatalf("Error in schema %v", err.Error())
+	}
+
+	expected := `"""Caches the result of a field."""
+directive @cached(ttl: Int = 60) on FIELD | FRAGMENT_SPREAD
+
+enum Color {
+  BLUE @deprecated
+
+  """The color of grass."""
+  GREEN
+  RED
+}
+
+input Filter {
+  color: Color = RED
+  limit: Int = 10
+}
+
+"""
+A fruit.
+
+It grows on trees.
+"""
+type Fruit implements Named {
+  color: Color! @deprecated(reason: "Use \"colors\".")
+  name: String
+}
+
+interface Named {
+  name: String
+}
+
+type Query {
+  fruits(
+    filter: Filter = {color: BLUE, limit: 5}
+
+    """Returns the first n fruits."""
+    first: Int
+  ): [Fruit]
+}
+`
+	if printed := graphql.PrintSchema(&schema); printed != expected {
+		t.Fatalf("Unexpected result, Diff: %v", testutil.Diff(expected, printed))
+	}
+}
+
+type printedFilterKey string
+
+func TestPrintSchema_PrintsDefaultValuesOfMapsWithNamedKeys(t *testing.T) {
+	filterType := graphql.NewInputObject(graphql.InputObjectConfig{
+		Name: "Filter",
+		Fields: graphql.InputObjectConfigFieldMap{
+			"limit": &graphql.InputObjectFieldConfig{Type: graphql.Int},
+		},
+	})
+	schema, err := graphql.NewSchema(graphql.SchemaConfig{
+		Query: graphql.NewObject(graphql.ObjectConfig{
+			Name: "Query",
+			Fields: graphql.Fields{
+				"named": &graphql.Field{
+					Type: graphql.String,
+					Args: graphql.FieldConfigArgument{
+						{Name: "filter", Type: filterType, DefaultValue: map[printedFilterKey]any{"limit": 5}},
+					},
+				},
+				"numbered": &graphql.Field{
+					Type: graphql.String,
+					Args: graphql.FieldConfigArgument{
+						{Name: "filter", Type: filterType, DefaultValue: map[int]any{1: 5}},
+					},
+				},
+			},
+		}),
+	})
+	if err != nil {
+		t.Fatalf("Error in schema %v", err.Error())
+	}
+
+	expected := `input Filter {
+  limit: Int
+}
+
+type Query {
+  named(filter: Filter = {limit: 5}): String
+  numbered(filter: Filter = {}): String
+}
+`
+	if printed := graphql.PrintSchema(&schema); printed != expected {
+		t.Fatalf("Unexpected result, Diff: %v", testutil.Diff(expected, printed))
+	}
+}
+
+func TestPrintSchema_RoundTripsBuiltSchemas(t *testing.T) {
+	sdl := `schema {
+  query: Root
+  subscription: Events
+}
+
+type Events {
+  ticks: Int
+}
+
+union Result = Root | Events
+
+type Root {
+  """
+  A long description of a field, which does not fit on a single line of a schema.
+  """
+  search(text: String!): [Result!]!
+}
+`
+	schema, err := graphql.BuildSchema(sdl, graphql.BuildSchemaOptions{})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if printed := graphql.PrintSchema(&schema); printed != sdl {
+		t.Fatalf("Unexpected result, Diff: %v", testutil.Diff(sdl, printed))
+	}
+}
+
+func TestPrintIntrospectionSchema_PrintsOnlyIntrospectionTypes(t *testing.T) {
+	schema := buildTestSchema(t)
+	printed := graphql.PrintIntrospectionSchema(&schema)
+	for _, expected := range []string{"directive @skip(", "type __Schema {", "enum __TypeKind {"} {
+		if !strings.Contains(printed, expected) {
+			t.Fatalf("expected %q in %s", expected, printed)
+		}
+	}
+	if strings.Contains(printed, "type Root") || strings.Contains(printed, "schema {") {
+		t.Fatalf("unexpected schema types in %s", printed)
+	}
+}
@@ -23,7 +23,6 @@ type SubscribeParams struct {
 
 // Subscribe performs a subscribe operation on the given query and schema
 // To finish a subscription you can simply close the channel from inside the `Subscribe` function
-// currently does not support extensions hooks
 func Subscribe(p Params) chan *Result {
 
 	source := source.NewSource(&source.Source{
@@ -31,15 +30,55 @@ func Subscribe(p Params) chan *Result {
 		Name: "GraphQL request",
 	})
 
-	// TODO run extensions hooks
+	// run init on the extensions
+	extErrs := handleExtensionsInits(&p)
+	if len(extErrs) != 0 {
+		return sendOneResultAndClose(&Result{
+			Errors: extErrs,
+		})
+	}
+
+	extErrs, parseFinishFn := handleExtensionsParseDidStart(&p)
+	if len(extErrs) != 0 {
+		return sendOneResultAndClose(&Result{
+			Errors: extErrs,
+		})
+	}
 
 	// parse the source
 	AST, err := parser.Parse(parser.ParseParams{Source: source})
 	if err != nil {
+		// run parseFinishFuncs for extensions
+		extErrs = parseFinishFn(err)
 
 		// merge the errors from extensions and the original error from parser
+		extErrs = append(extErrs, gqlerrors.FormatErrors(err)...)
+		return sendOneResultAndClose(&Result{
+			Errors: extErrs,
+		})
+	}
+
+	// run parseFinish functions for extensions
+	extErrs = parseFinishFn(err)
+	if len(extErrs) != 0 {
 		return sendOneResultAndClose(&Result{
-			Errors: gqlerrors.FormatErrors(err),
+			Errors: extErrs,
+		})
+	}
+
+	// let extensions rewrite the document
+	AST, extErrs = handleExtensionsRewriteDocument(&p, AST)
+	if len(extErrs) != 0 {
+		return sendOneResultAndClose(&Result{
+			Errors: extErrs,
+		})
+	}
+
+	// notify extensions about the start of the validation
+	extErrs, validationFinishFn := handleExtensionsValidationDidStart(&p)
+	if len(extErrs) != 0 {
+		return sendOneResultAndClose(&Result{
+			Errors: extErrs,
 		})
 	}
 
@@ -48,18 +87,40 @@ func Subscribe(p Params) chan *Result {
 
 	if !validationResult.IsValid {
 		// run validation finish functions for extensions
+		extErrs = validationFinishFn(validationResult.Errors)
+
+		// merge the errors from extensions and the original error from parser
+		extErrs = append(extErrs, validationResult.Errors...)
+		return sendOneResultAndClose(&Result{
+			Errors: extErrs,
+		})
+	}
+
+	// run the validationFinishFuncs for extensions
+	extErrs = validationFinishFn(validationResult.Errors)
+	if len(extErrs) != 0 {
 		return sendOneResultAndClose(&Result{
-			Errors: validationResult.Errors,
+			Errors: extErrs,
 		})
+	}
 
+	// let extensions rewrite the variables
+	extErrs = handleExtensionsRewriteVariableValues(&p)
+	if len(extErrs) != 0 {
+		return sendOneResultAndClose(&Result{
+			Errors: extErrs,
+		})
 	}
+
 	return ExecuteSubscription(ExecuteParams{
-		Schema:        p.Schema,
-		Root:          p.RootObject,
-		AST:           AST,
-		OperationName: p.OperationName,
-		Args:          p.VariableValues,
-		Context:       p.Context,
+		Schema:         p.Schema,
+		Root:           p.RootObject,
+		AST:            AST,
+		OperationName:  p.OperationName,
+		Args:           p.VariableValues,
+		Context:        p.Context,
+		OrderedResults: p.OrderedResults,
+		Parallelism:    p.Parallelism,
 	})
 }
 
@@ -70,33 +131,60 @@ func sendOneResultAndClose(res *Result) chan *Result {
 	return resultChannel
 }
 
-// ExecuteSubscription is similar to graphql.Execute but returns a channel instead of a Result
-// currently does not support extensions
+// ExecuteSubscription is similar to graphql.Execute but returns a channel instead of a Result.
+// Each event of the subscription is executed with graphql.Execute, so extensions are
+// notified of every execution, and the whole subscription is wrapped by the hooks of
+// the extensions implementing SubscriptionExtension.
 func ExecuteSubscription(p ExecuteParams) chan *Result {
 
 	if p.Context == nil {
 		p.Context = context.Background()
 	}
 
+	// notify extensions about the start of the subscription
+	extErrs, subscriptionFinishFn := handleExtensionsSubscriptionDidStart(&p)
+	if len(extErrs) != 0 {
+		return sendOneResultAndClose(&Result{
+			Errors: extErrs,
+		})
+	}
+
 	var mapSourceToResponse = func(payload any) *Result {
 		return Execute(ExecuteParams{
-			Schema:        p.Schema,
-			Root:          payload,
-			AST:           p.AST,
-			OperationName: p.OperationName,
-			Args:          p.Args,
-			Context:       p.Context,
+			Schema:         p.Schema,
+			Root:           payload,
+			AST:            p.AST,
+			OperationName:  p.OperationName,
+			Args:           p.Args,
+			Context:        p.Context,
+			OrderedResults: p.OrderedResults,
+			Parallelism:    p.Parallelism,
 		})
 	}
 	var resultChannel = make(chan *Result)
 	go func() {
+		// the error ending the subscription, if any
+		var subscriptionErr error
+
 		defer close(resultChannel)
+		defer func() {
+			// run the subscriptionFinishFuncs for extensions, their errors are
+			// sent unless nobody listens anymore
+			extErrs := subscriptionFinishFn(subscriptionErr)
+			if len(extErrs) != 0 {
+				select {
+				case resultChannel <- &Result{Errors: extErrs}:
+				case <-p.Context.Done():
+				}
+			}
+		}()
 		defer func() {
 			if err := recover(); err != nil {
 				e, ok := err.(error)
 				if !ok {
 					return
 				}
+				subscriptionErr = e
 				resultChannel <- &Result{
 					Errors: gqlerrors.FormatErrors(e),
 				}
@@ -114,6 +202,7 @@ func ExecuteSubscription(p ExecuteParams) chan *Result {
 		})
 
 		if err != nil {
+			subscriptionErr = err
 			resultChannel <- &Result{
 				Errors: gqlerrors.FormatErrors(err),
 			}
@@ -123,6 +212,7 @@ func ExecuteSubscription(p ExecuteParams) chan *Result {
 
 		operationType, err := getOperationRootType(p.Schema, exeContext.Operation)
 		if err != nil {
+			subscriptionErr = err
 			resultChannel <- &Result{
 				Errors: gqlerrors.FormatErrors(err),
 			}
@@ -147,8 +237,9 @@ func ExecuteSubscription(p ExecuteParams) chan *Result {
 		fieldDef := getFieldDef(p.Schema, operationType, fieldName)
 
 		if fieldDef == nil {
+			subscriptionErr = fmt.Errorf("the subscription field %q is not defined", fieldName)
 			resultChannel <- &Result{
-				Errors: gqlerrors.FormatErrors(fmt.Errorf("the subscription field %q is not defined", fieldName)),
+				Errors: gqlerrors.FormatErrors(subscriptionErr),
 			}
 
 			return
@@ -157,8 +248,9 @@ func ExecuteSubscription(p ExecuteParams) chan *Result {
 		resolveFn := fieldDef.Subscribe
 
 		if resolveFn == nil {
+			subscriptionErr = fmt.Errorf("the subscription function %q is not defined", fieldName)
 			resultChannel <- &Result{
-				Errors: gqlerrors.FormatErrors(fmt.Errorf("the subscription function %q is not defined", fieldName)),
+				Errors: gqlerrors.FormatErrors(subscriptionErr),
 			}
 			return
 		}
@@ -187,6 +279,7 @@ func ExecuteSubscription(p ExecuteParams) chan *Result {
 			Context: p.Context,
 		})
 		if err != nil {
+			subscriptionErr = err
 			resultChannel <- &Result{
 				Errors: gqlerrors.FormatErrors(err),
 			}
@@ -195,8 +288,9 @@ func ExecuteSubscription(p ExecuteParams) chan *Result {
 		}
 
 		if fieldResult == nil {
+			subscriptionErr = fmt.Errorf("no field result")
 			resultChannel <- &Result{
-				Errors: gqlerrors.FormatErrors(fmt.Errorf("no field result")),
+				Errors: gqlerrors.FormatErrors(subscriptionErr),
 			}
 
 			return
@@ -208,6 +302,7 @@ func ExecuteSubscription(p ExecuteParams) chan *Result {
 			for {
 				select {
 				case <-p.Context.Done():
+					subscriptionErr = p.Context.Err()
 					return
 
 				case res, more := <-sub:
@@ -306,6 +306,18 @@ func init() {
 			},
 		},
 	})
+	var oneOfInputObject = graphql.NewInputObject(graphql.InputObjectConfig{
+		Name:    "OneOfInput",
+		IsOneOf: true,
+		Fields: graphql.InputObjectConfigFieldMap{
+			"stringField": &graphql.InputObjectFieldConfig{
+				Type: graphql.String,
+			},
+			"intField": &graphql.InputObjectFieldConfig{
+				Type: graphql.Int,
+			},
+		},
+	})
 	var complicatedArgs = graphql.NewObject(graphql.ObjectConfig{
 		Name: "ComplicatedArgs",
 		// TODO List
@@ -393,6 +405,15 @@ func init() {
 					},
 				},
 			},
+			"oneOfArgField": &graphql.Field{
+				Type: graphql.String,
+				Args: graphql.FieldConfigArgument{
+					&graphql.ArgumentConfig{
+						Name: "oneOfArg",
+						Type: oneOfInputObject,
+					},
+				},
+			},
 			"multipleReqs": &graphql.Field{
 				Type: graphql.String,
 				Args: graphql.FieldConfigArgument{
@@ -505,6 +526,11 @@ func init() {
 				Name:      "onField",
 				Locations: []string{graphql.DirectiveLocationField},
 			}),
+			graphql.NewDirective(graphql.DirectiveConfig{
+				Name:         "repeatable",
+				Locations:    []string{graphql.DirectiveLocationField},
+				IsRepeatable: true,
+			}),
 			graphql.NewDirective(graphql.DirectiveConfig{
 				Name:      "onFragmentDefinition",
 				Locations: []string{graphql.DirectiveLocationFragmentDefinition},
@@ -593,6 +619,10 @@ func expectValidRule(t *testing.T, schema *graphql.Schema, rules []graphql.Valid
 
 }
 func expectInvalidRule(t *testing.T, schema *graphql.Schema, rules []graphql.ValidationRuleFn, queryString string, expectedErrors []gqlerrors.FormattedError) {
+	t.Helper()
+	expectInvalidRuleWithVariables(t, schema, rules, queryString, nil, expectedErrors)
+}
+func expectInvalidRuleWithVariables(t *testing.T, schema *graphql.Schema, rules []graphql.ValidationRuleFn, queryString string, variableValues map[string]any, expectedErrors []gqlerrors.FormattedError) {
 	t.Helper()
 	source := source.NewSource(&source.Source{
 		Body: []byte(queryString),
@@ -601,7 +631,7 @@ func expectInvalidRule(t *testing.T, schema *graphql.Schema, rules []graphql.Val
 	if err != nil {
 		t.Fatal(err)
 	}
-	result := graphql.ValidateDocument(schema, AST, rules)
+	result := graphql.ValidateDocumentWithVariables(schema, AST, rules, variableValues)
 	if len(result.Errors) != len(expectedErrors) {
 		t.Fatalf("Should have %v errors, got %v", len(expectedErrors), len(result.Errors))
 	}
@@ -632,6 +662,10 @@ func ExpectFailsRule(t *testing.T, rule graphql.ValidationRuleFn, queryString st
 func ExpectFailsRuleWithSchema(t *testing.T, schema *graphql.Schema, rule graphql.ValidationRuleFn, queryString string, expectedErrors []gqlerrors.FormattedError) {
 	expectInvalidRule(t, schema, []graphql.ValidationRuleFn{rule}, queryString, expectedErrors)
 }
+func ExpectFailsRuleWithVariables(t *testing.T, schema *graphql.Schema, rule graphql.ValidationRuleFn, queryString string, variableValues map[string]any, expectedErrors []gqlerrors.FormattedError) {
+	t.Helper()
+	expectInvalidRuleWithVariables(t, schema, []graphql.ValidationRuleFn{rule}, queryString, variableValues, expectedErrors)
+}
 func ExpectPassesRuleWithSchema(t *testing.T, schema *graphql.Schema, rule graphql.ValidationRuleFn, queryString string) {
 	expectValidRule(t, schema, []graphql.ValidationRuleFn{rule}, queryString)
 }
@@ -0,0 +1,300 @@
+package graphql
+
+import (
+	"context"
+	"fmt"
+	"sort"
+	"sync"
+	"time"
+
+	"github.com/dagger/graphql/gqlerrors"
+)
+
+// Tracer emits the spans recorded by a TracingExtension, e.g. to OpenTelemetry.
+// Implementations must be safe for concurrent use, as fields may be resolved
+// in parallel.
+type Tracer interface {
+	// StartSpan starts a span as a child of the span held by the given
+	// context, if any. It returns a context holding the new span, and a
+	// function ending the span with the error of the traced operation.
+	StartSpan(ctx context.Context, name string, attributes map[string]any) (context.Context, func(error))
+}
+
+// TracingResult is the result added by a TracingExtension to the extensions
+// of a Result, in the Apollo tracing format. Offsets are relative to
+// StartTime, and durations are marshalled in nanoseconds.
+type TracingResult struct {
+	Version    int              `json:"version"`
+	StartTime  time.Time        `json:"startTime"`
+	EndTime    time.Time        `json:"endTime"`
+	Duration   time.Duration    `json:"duration"`
+	Parsing    TracingPhase     `json:"parsing"`
+	Validation TracingPhase     `json:"validation"`
+	Execution  TracingExecution `json:"execution"`
+}
+
+// TracingPhase holds the timing of a phase of a request.
+type TracingPhase struct {
+	StartOffset time.Duration `json:"startOffset"`
+	Duration    time.Duration `json:"duration"`
+}
+
+// TracingExecution holds the timings of the resolvers run by an execution.
+type TracingExecution struct {
+	Resolvers []*TracingResolver `json:"resolvers"`
+}
+
+// TracingResolver holds the timing of the resolver of a field.
+type TracingResolver struct {
+	Path        []any         `json:"path"`
+	ParentType  string        `json:"parentType"`
+	FieldName   string        `json:"fieldName"`
+	ReturnType  string        `json:"returnType"`
+	StartOffset time.Duration `json:"startOffset"`
+	Duration    time.Duration `json:"duration"`
+}
+
+// TracingExtension is an Extension recording the timings of parsing,
+// validation, execution and of every field resolver, returned under the
+// "tracing" key of the extensions of a Result. Spans are also emitted to its
+// Tracer, if any: the span of a field is a child of the span of the field
+// whose value it belongs to, and those of root fields of the execution span.
+type TracingExtension struct {
+	tracer Tracer
+}
+
+// NewTracingExtension creates a TracingExtension emitting spans to the given
+// tracer, which may be nil.
+func NewTracingExtension(tracer Tracer) *TracingExtension {
+	return &TracingExtension{
+		tracer: tracer,
+	}
+}
+
+type tracingContextKey struct{}
+
+// tracingState holds the timings of a single request.
+type tracingState struct {
+	mu     sync.Mutex
+	result *TracingResult
+}
+
+func (s *tracingState) offset(t time.Time) time.Duration {
+	return t.Sub(s.result.StartTime)
+}
+
+var _ FullExtension = (*TracingExtension)(nil)
+
+// Init implements Extension.
+func (t *TracingExtension) Init(ctx context.Context, p *Params) context.Context {
+	if ctx == nil {
+		ctx = context.Background()
+	}
+	return t.withState(ctx)
+}
+
+// withState returns a context holding the timings of a new request, unless the
+// given context already holds them.
+func (t *TracingExtension) withState(ctx context.Context) context.Context {
+	if _, ok := ctx.Value(tracingContextKey{}).(*tracingState); ok {
+		return ctx
+	}
+	return context.WithValue(ctx, tracingContextKey{}, &tracingState{
+		result: &TracingResult{
+			Version:   1,
+			StartTime: time.Now(),
+			Execution: TracingExecution{
+				Resolvers: []*TracingResolver{},
+			},
+		},
+	})
+}
+
+// Name implements Extension.
+func (t *TracingExtension) Name() string {
+	return "tracing"
+}
+
+// ParseDidStart implements Extension.
+func (t *TracingExtension) ParseDidStart(ctx context.Context) (context.Context, ParseFinishFunc) {
+	finish := t.tracePhase(ctx, "parse", func(s *tracingState) *TracingPhase {
+		return &s.result.Parsing
+	})
+	return ctx, finish
+}
+
+// ValidationDidStart implements Extension.
+func (t *TracingExtension) ValidationDidStart(ctx context.Context) (context.Context, ValidationFinishFunc) {
+	finish := t.tracePhase(ctx, "validation", func(s *tracingState) *TracingPhase {
+		return &s.result.Validation
+	})
+	return ctx, func(errs []gqlerrors.FormattedError) {
+		if len(errs) != 0 {
+			finish(errs[0])
+			return
+		}
+		finish(nil)
+	}
+}
+
+// tracePhase times the parsing or validation of a request. The span of the
+// phase is not kept in the context, so that it doesn't become the parent of
+// the spans of the next phases.
+func (t *TracingExtension) tracePhase(ctx context.Context, name string, phase func(*tracingState) *TracingPhase) func(error) {
+	state, _ := ctx.Value(tracingContextKey{}).(*tracingState)
+	endSpan := func(error) {}
+	if t.tracer != nil {
+		_, endSpan = t.tracer.StartSpan(ctx, name, nil)
+	}
+	start := time.Now()
+	return func(err error) {
+		end := time.Now()
+		if state != nil {
+			state.mu.Lock()
+			*phase(state) = TracingPhase{
+				StartOffset: state.offset(start),
+				Duration:    end.Sub(start),
+			}
+			state.mu.Unlock()
+		}
+		endSpan(err)
+	}
+}
+
+// ExecutionDidStart implements Extension.
+func (t *TracingExtension) ExecutionDidStart(ctx context.Context) (context.Context, ExecutionFinishFunc) {
+	if ctx == nil {
+		ctx = context.Background()
+	}
+	// Execute may be called without Do, and so without Init
+	ctx = t.withState(ctx)
+	state := ctx.Value(tracingContextKey{}).(*tracingState)
+
+	endSpan := func(error) {}
+	if t.tracer != nil {
+		ctx, endSpan = t.tracer.StartSpan(ctx, "execution", nil)
+	}
+	return ctx, func(result *Result) {
+		end := time.Now()
+		state.mu.Lock()
+		state.result.EndTime = end
+		state.result.Duration = state.offset(end)
+		state.mu.Unlock()
+
+		if result != nil && len(result.Errors) != 0 {
+			endSpan(result.Errors[0])
+			return
+		}
+		endSpan(nil)
+	}
+}
+
+// ResolveFieldDidStart implements Extension.
+func (t *TracingExtension) ResolveFieldDidStart(ctx context.Context, info *ResolveInfo) (context.Context, ResolveFieldFinishFunc) {
+	state, _ := ctx.Value(tracingContextKey{}).(*tracingState)
+	resolver := &TracingResolver{
+		Path:       info.Path.AsArray(),
+		ParentType: info.ParentType.Name(),
+		FieldName:  info.FieldName,
+		ReturnType: info.ReturnType.String(),
+	}
+
+	endSpan := func(error) {}
+	if t.tracer != nil {
+		ctx, endSpan = t.tracer.StartSpan(ctx, fmt.Sprintf("%v.%v", resolver.ParentType, resolver.FieldName), map[string]any{
+			"path":       resolver.Path,
+			"parentType": resolver.ParentType,
+			"fieldName":  resolver.FieldName,
+			"returnType": resolver.ReturnType,
+		})
+	}
+	start := time.Now()
+	return ctx, func(v any, err error) {
+		end := time.Now()
+		if state != nil {
+			state.mu.Lock()
+			resolver.StartOffset = state.offset(start)
+			resolver.Duration = end.Sub(start)
+			state.result.Execution.Resolvers = append(state.result.Execution.Resolvers, resolver)
+			state.mu.Unlock()
+		}
+		endSpan(err)
+	}
+}
+
+// HasResult implements Extension.
+func (t *TracingExtension) HasResult() bool {
+	return true
+}
+
+// GetResult implements Extension, returning a *TracingResult.
+func (t *TracingExtension) GetResult(ctx context.Context) any {
+	state, ok := ctx.Value(tracingContextKey{}).(*tracingState)
+	if !ok {
+		return nil
+	}
+	state.mu.Lock()
+	defer state.mu.Unlock()
+
+	result := *state.result
+	result.Execution.Resolvers = append([]*TracingResolver{}, state.result.Execution.Resolvers...)
+	sort.SliceStable(result.Execution.Resolvers, func(i, j int) bool {
+		return result.Execution.Resolvers[i].StartOffset < result.Execution.Resolvers[j].StartOffset
+	})
+	return &result
+}
+
+// RecordedSpan is a span recorded by an InMemoryTracer.
+type RecordedSpan struct {
+	Name       string
+	Attributes map[string]any
+	// Parent is the span in the context the span was started with, if any.
+	Parent    *RecordedSpan
+	StartTime time.Time
+	EndTime   time.Time
+	Err       error
+	Ended     bool
+}
+
+// InMemoryTracer is a Tracer recording spans in memory, e.g. for tests. It is
+// safe for concurrent use.
+type InMemoryTracer struct {
+	mu    sync.Mutex
+	spans []*RecordedSpan
+}
+
+type recordedSpanContextKey struct{}
+
+// NewInMemoryTracer creates an InMemoryTracer without any span.
+func NewInMemoryTracer() *InMemoryTracer {
+	return &InMemoryTracer{}
+}
+
+// StartSpan implements Tracer.
+func (r *InMemoryTracer) StartSpan(ctx context.Context, name string, attributes map[string]any) (context.Context, func(error)) {
+	parent, _ := ctx.Value(recordedSpanContextKey{}).(*RecordedSpan)
+	span := &RecordedSpan{
+		Name:       name,
+		Attributes: attributes,
+		Parent:     parent,
+		StartTime:  time.Now(),
+	}
+	r.mu.Lock()
+	r.spans = append(r.spans, span)
+	r.mu.Unlock()
+
+	return context.WithValue(ctx, recordedSpanContextKey{}, span), func(err error) {
+		r.mu.Lock()
+		defer r.mu.Unlock()
+		span.EndTime = time.Now()
+		span.Err = err
+		span.Ended = true
+	}
+}
+
+// Spans returns the recorded spans, in the order they were started.
+func (r *InMemoryTracer) Spans() []*RecordedSpan {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	return append([]*RecordedSpan{}, r.spans...)
+}
@@ -0,0 +1,168 @@
+package graphql_test
+
+import (
+	"encoding/json"
+	"errors"
+	"reflect"
+	"testing"
+
+	"github.com/dagger/graphql"
+	"github.com/dagger/graphql/testutil"
+)
+
+func tracingSchema(t *testing.T, tracer graphql.Tracer) graphql.Schema {
+	userType := graphql.NewObject(graphql.ObjectConfig{
+		Name: "User",
+		Fields: graphql.Fields{
+			"name": &graphql.Field{
+				Type: graphql.String,
+			},
+			"friend": &graphql.Field{
+				Type: graphql.String,
+				Resolve: func(p graphql.ResolveParams) (any, error) {
+					return nil, errors.New("no friend")
+				},
+			},
+		},
+	})
+	schema, err := graphql.NewSchema(graphql.SchemaConfig{
+		Query: graphql.NewObject(graphql.ObjectConfig{
+			Name: "Query",
+			Fields: graphql.Fields{
+				"users": &graphql.Field{
+					Type: graphql.NewList(userType),
+					Resolve: func(p graphql.ResolveParams) (any, error) {
+						return []map[string]any{{"name": "alice"}}, nil
+					},
+				},
+			},
+		}),
+		Extensions: []graphql.Extension{graphql.NewTracingExtension(tracer)},
+	})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	return schema
+}
+
+func TestTracingExtension_ReturnsApolloTracing(t *testing.T) {
+	result := graphql.Do(graphql.Params{
+		Schema:        tracingSchema(t, nil),
+		RequestString: `{ users { name } }`,
+	})
+	if len(result.Errors) > 0 {
+		t.Fatalf("unexpected errors: %v", result.Errors)
+	}
+	tracing, ok := result.Extensions["tracing"].(*graphql.TracingResult)
+	if !ok {
+		t.Fatalf("expected a tracing result, got: %v", result.Extensions)
+	}
+	if tracing.Version != 1 {
+		t.Fatalf("expected version 1, got: %v", tracing.Version)
+	}
+	if tracing.EndTime.Before(tracing.StartTime) || tracing.Duration != tracing.EndTime.Sub(tracing.StartTime) {
+		t.Fatalf("unexpected request timing: %v - %v (%v)", tracing.StartTime, tracing.EndTime, tracing.Duration)
+	}
+	if tracing.Validation.StartOffset < tracing.Parsing.StartOffset+tracing.Parsing.Duration {
+		t.Fatalf("expected validation to start after parsing: %+v %+v", tracing.Parsing, tracing.Validation)
+	}
+
+	type resolver struct {
+		Path       []any
+		ParentType string
+		FieldName  string
+		ReturnType string
+	}
+	expected := []resolver{
+		{[]any{"users"}, "Query", "users", "[User]"},
+		{[]any{"users", 0, "name"}, "User", "name", "String"},
+	}
+	resolvers := []resolver{}
+	for _, r := range tracing.Execution.Resolvers {
+		if r.StartOffset < tracing.Validation.StartOffset || r.Duration < 0 {
+			t.Fatalf("unexpected resolver timing: %+v", r)
+		}
+		resolvers = append(resolvers, resolver{r.Path, r.ParentType, r.FieldName, r.ReturnType})
+	}
+	if !reflect.DeepEqual(expected, resolvers) {
+		t.Fatalf("Unexpected resolvers, Diff: %v", testutil.Diff(expected, resolvers))
+	}
+
+	b, err := json.Marshal(result.Extensions)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	var marshalled struct {
+		Tracing struct {
+			Version   int    `json:"version"`
+			StartTime string `json:"startTime"`
+			Duration  int64  `json:"duration"`
+			Parsing   struct {
+				Duration *int64 `json:"duration"`
+			} `json:"parsing"`
+			Execution struct {
+				Resolvers []map[string]any `json:"resolvers"`
+			} `json:"execution"`
+		} `json:"tracing"`
+	}
+	if err := json.Unmarshal(b, &marshalled); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if marshalled.Tracing.Version != 1 || marshalled.Tracing.StartTime == "" || marshalled.Tracing.Parsing.Duration == nil ||
+		marshalled.Tracing.Duration != int64(tracing.Duration) || len(marshalled.Tracing.Execution.Resolvers) != 2 {
+		t.Fatalf("unexpected marshalled tracing: %s", b)
+	}
+}
+
+func TestTracingExtension_EmitsSpans(t *testing.T) {
+	tracer := graphql.NewInMemoryTracer()
+	result := graphql.Do(graphql.Params{
+		Schema:        tracingSchema(t, tracer),
+		RequestString: `{ users { name friend } }`,
+	})
+	if len(result.Errors) != 1 {
+		t.Fatalf("expected a single error, got: %v", result.Errors)
+	}
+
+	type span struct {
+		Name   string
+		Parent string
+		Err    string
+	}
+	spans := []span{}
+	for _, s := range tracer.Spans() {
+		if !s.Ended || s.EndTime.Before(s.StartTime) {
+			t.Fatalf("unexpected span timing: %+v", s)
+		}
+		recorded := span{Name: s.Name}
+		if s.Parent != nil {
+			recorded.Parent = s.Parent.Name
+		}
+		if s.Err != nil {
+			recorded.Err = s.Err.Error()
+		}
+		spans = append(spans, recorded)
+	}
+	expected := []span{
+		{Name: "parse"},
+		{Name: "validation"},
+		{Name: "execution", Err: "no friend"},
+		{Name: "Query.users", Parent: "execution"},
+		{Name: "User.name", Parent: "Query.users"},
+		{Name: "User.friend", Parent: "Query.users", Err: "no friend"},
+	}
+	if !reflect.DeepEqual(expected, spans) {
+		t.Fatalf("Unexpected spans, Diff: %v", testutil.Diff(expected, spans))
+	}
+
+	attributes := tracer.Spans()[4].Attributes
+	expectedAttributes := map[string]any{
+		"path":       []any{"users", 0, "name"},
+		"parentType": "User",
+		"fieldName":  "name",
+		"returnType": "String",
+	}
+	if !reflect.DeepEqual(expectedAttributes, attributes) {
+		t.Fatalf("Unexpected attributes, Diff: %v", testutil.Diff(expectedAttributes, attributes))
+	}
+}
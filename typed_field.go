@@ -0,0 +1,114 @@
+package graphql
+
+import (
+	"context"
+	"fmt"
+	"reflect"
+	"strings"
+	"sync"
+	"unicode"
+)
+
+// TypedResolveFn resolves a field from its source and arguments, decoded into
+// Go types.
+type TypedResolveFn[Src, Args, Ret any] func(ctx context.Context, source Src, args Args) (Ret, error)
+
+// TypedField returns a copy of the given field resolved by resolve, which is
+// given the source of the field as a Src and its arguments decoded into an
+// Args by DecodeArgs.
+//
+// Args is either map[string]any, to get the arguments as is, or a struct
+// (or a pointer to one) whose exported fields are the arguments. Arguments
+// are named by the `graphql` tag of their field, else by its `json` tag, else
+// by its name with its leading capitals lowercased, and fields tagged "-"
+// are skipped. Unless the field already has Args, they are derived from the
+// struct following the rules of Reflector, by a Reflector shared by all typed
+// fields: strings, bools, integers, floats and time.Time map to the matching
+// scalars, slices to lists, structs to input objects, and fields are non-null
+// unless they are pointers. Fields of other types require Args to be given
+// explicitly.
+func TypedField[Src, Args, Ret any](field Field, resolve TypedResolveFn[Src, Args, Ret]) *Field {
+	argsType := reflect.TypeOf((*Args)(nil)).Elem()
+	if field.Args == nil {
+		field.Args, field.err = argumentsOf(argsType)
+	} else if !isArgsStruct(argsType) && argsType != reflect.TypeOf(map[string]any{}) {
+		field.err = fmt.Errorf("TypedField arguments must be decoded into a struct or map[string]any but got: %v.", argsType)
+	}
+
+	field.Resolve = func(p ResolveParams) (any, error) {
+		source, ok := p.Source.(Src)
+		if !ok && p.Source != nil {
+			return nil, fmt.Errorf("expected source of type %v but got: %T", reflect.TypeOf((*Src)(nil)).Elem(), p.Source)
+		}
+		var args Args
+		if err := decodeArgs(p.Args, reflect.ValueOf(&args).Elem()); err != nil {
+			return nil, err
+		}
+		return resolve(p.Context, source, args)
+	}
+	return &field
+}
+
+func isArgsStruct(t reflect.Type) bool {
+	if t.Kind() == reflect.Ptr {
+		t = t.Elem()
+	}
+	return t.Kind() == reflect.Struct
+}
+
+// typedFieldReflector maps the arguments of typed fields to input types,
+// shared so that the types of all typed fields are built once.
+var typedFieldReflector = struct {
+	sync.Mutex
+	*Reflector
+}{Reflector: NewReflector()}
+
+// argumentsOf derives the arguments of a field from the type its arguments
+// are decoded into.
+func argumentsOf(t reflect.Type) (FieldConfigArgument, error) {
+	if t == reflect.TypeOf(map[string]any{}) {
+		return FieldConfigArgument{}, nil
+	}
+	if !isArgsStruct(t) {
+		return FieldConfigArgument{}, fmt.Errorf("TypedField arguments must be decoded into a struct or map[string]any but got: %v.", t)
+	}
+	typedFieldReflector.Lock()
+	defer typedFieldReflector.Unlock()
+	return typedFieldReflector.Arguments(t)
+}
+
+// argumentName returns the name of the argument or input field decoded into
+// the given struct field, following the tag rules of DefaultResolveFn, and
+// false if the field is unexported or tagged "-".
+func argumentName(field reflect.StructField) (string, bool) {
+	if field.PkgPath != "" {
+		return "", false
+	}
+	for _, tagName := range []string{"graphql", "json"} {
+		name := strings.Split(field.Tag.Get(tagName), ",")[0]
+		if name == "-" {
+			return "", false
+		}
+		if name != "" {
+			return name, true
+		}
+	}
+	return lowerCamel(field.Name), true
+}
+
+// lowerCamel lowercases the leading capitals of a Go identifier, keeping the
+// last one capitalized when followed by a lowercase letter, e.g. ID is id and
+// HTTPServer is httpServer.
+func lowerCamel(name string) string {
+	runes := []rune(name)
+	for i := range runes {
+		if !unicode.IsUpper(runes[i]) {
+			break
+		}
+		if i > 0 && i+1 < len(runes) && unicode.IsLower(runes[i+1]) {
+			break
+		}
+		runes[i] = unicode.ToLower(runes[i])
+	}
+	return string(runes)
+}
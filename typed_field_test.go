@@ -0,0 +1,176 @@
+package graphql_test
+
+import (
+	"context"
+	"fmt"
+	"reflect"
+	"strings"
+	"testing"
+
+	"github.com/dagger/graphql"
+	"github.com/dagger/graphql/testutil"
+)
+
+type typedUser struct {
+	Name string
+}
+
+type greetArgs struct {
+	Greeting string   `graphql:"greeting"`
+	Times    *int     `json:"times,omitempty"`
+	Tags     []string `json:"tags"`
+	Loud     bool
+	internal string
+	Ignored  string `graphql:"-"`
+}
+
+func checkTypedField(t *testing.T, field *graphql.Field, query string, args map[string]any, expected any) {
+	userType := graphql.NewObject(graphql.ObjectConfig{
+		Name: "User",
+		Fields: graphql.Fields{
+			"name":  &graphql.Field{Type: graphql.String},
+			"field": field,
+		},
+	})
+	schema, err := graphql.NewSchema(graphql.SchemaConfig{
+		Query: graphql.NewObject(graphql.ObjectConfig{
+			Name: "Query",
+			Fields: graphql.Fields{
+				"user": &graphql.Field{
+					Type: userType,
+					Resolve: func(p graphql.ResolveParams) (any, error) {
+						return &typedUser{Name: "alice"}, nil
+					},
+				},
+			},
+		}),
+	})
+	if err != nil {
+		t.Fatalf("Error in schema %v", err.Error())
+	}
+
+	result := testutil.TestExecute(t, graphql.ExecuteParams{
+		Schema: schema,
+		AST:    testutil.TestParse(t, query),
+		Args:   args,
+	})
+	expectedResult := &graphql.Result{
+		Data: map[string]any{"user": expected},
+	}
+	if !testutil.EqualResults(expectedResult, result) {
+		t.Fatalf("Unexpected result, Diff: %v", testutil.Diff(expectedResult, result))
+	}
+}
+
+func TestTypedField_DecodesSourceAndArgs(t *testing.T) {
+	greet := graphql.TypedField(graphql.Field{Type: graphql.String}, func(ctx context.Context, user *typedUser, args greetArgs) (string, error) {
+		greeting := fmt.Sprintf("%v %v", args.Greeting, user.Name)
+		if args.Times != nil {
+			greeting = strings.Repeat(greeting+" ", *args.Times)
+		}
+		if args.Loud {
+			greeting = strings.ToUpper(greeting)
+		}
+		return strings.Join(append(strings.Fields(greeting), args.Tags...), " "), nil
+	})
+	query := `query ($times: Int) {
+		user {
+			plain: field(greeting: "hi", tags: [], loud: false)
+			repeated: field(greeting: "hey", times: $times, tags: ["a", "b"], loud: true)
+		}
+	}`
+	expected := map[string]any{
+		"plain":    "hi alice",
+		"repeated": "HEY ALICE HEY ALICE a b",
+	}
+	checkTypedField(t, greet, query, map[string]any{"times": 2}, expected)
+}
+
+func TestTypedField_DerivesArgs(t *testing.T) {
+	greet := graphql.TypedField(graphql.Field{Type: graphql.String}, func(ctx context.Context, user *typedUser, args greetArgs) (string, error) {
+		return "", nil
+	})
+	args := map[string]string{}
+	for _, arg := range greet.Args {
+		args[arg.Name] = arg.Type.String()
+	}
+	expected := map[string]string{
+		"greeting": "String!",
+		"times":    "Int",
+		"tags":     "[String!]!",
+		"loud":     "Boolean!",
+	}
+	if !reflect.DeepEqual(expected, args) {
+		t.Fatalf("Unexpected args, Diff: %v", testutil.Diff(expected, args))
+	}
+}
+
+func TestTypedField_KeepsExplicitArgs(t *testing.T) {
+	colorType := graphql.NewEnum(graphql.EnumConfig{
+		Name: "Color",
+		Values: graphql.EnumValueConfigMap{
+			"RED":  &graphql.EnumValueConfig{Value: "red"},
+			"BLUE": &graphql.EnumValueConfig{Value: "blue"},
+		},
+	})
+	type colorArgs struct {
+		Color string
+	}
+	favorite := graphql.TypedField(graphql.Field{
+		Type: graphql.String,
+		Args: graphql.FieldConfigArgument{
+			&graphql.ArgumentConfig{Name: "color", Type: colorType},
+		},
+	}, func(ctx context.Context, user any, args colorArgs) (string, error) {
+		return args.Color, nil
+	})
+	checkTypedField(t, favorite, `{ user { field(color: BLUE) } }`, nil, map[string]any{"field": "blue"})
+}
+
+func TestTypedField_RejectsUnsupportedArgs(t *testing.T) {
+	type badArgs struct {
+		Callback func()
+	}
+	_, err := graphql.NewSchema(graphql.SchemaConfig{
+		Query: graphql.NewObject(graphql.ObjectConfig{
+			Name: "Query",
+			Fields: graphql.Fields{
+				"bad": graphql.TypedField(graphql.Field{Type: graphql.String}, func(ctx context.Context, source any, args badArgs) (string, error) {
+					return "", nil
+				}),
+			},
+		}),
+	})
+	if err == nil || !strings.Contains(err.Error(), "cannot derive an input type from func()") {
+		t.Fatalf("expected an error about the unsupported argument, got: %v", err)
+	}
+}
+
+func TestTypedField_RejectsNonStructArgs(t *testing.T) {
+	_, err := graphql.NewSchema(graphql.SchemaConfig{
+		Query: graphql.NewObject(graphql.ObjectConfig{
+			Name: "Query",
+			Fields: graphql.Fields{
+				"bad": graphql.TypedField(graphql.Field{Type: graphql.String}, func(ctx context.Context, source any, args string) (string, error) {
+					return "", nil
+				}),
+			},
+		}),
+	})
+	expected := "TypedField arguments must be decoded into a struct or map[string]any but got: string."
+	if err == nil || !strings.Contains(err.Error(), expected) {
+		t.Fatalf("expected error %q, got: %v", expected, err)
+	}
+}
+
+func TestTypedField_DerivesInputObjectArgs(t *testing.T) {
+	type searchArgs struct {
+		Filter MemberFilter
+		Roles  []Role
+	}
+	search := graphql.TypedField(graphql.Field{Type: graphql.String}, func(ctx context.Context, user *typedUser, args searchArgs) (string, error) {
+		return fmt.Sprintf("%v %v %v", args.Filter.Role, *args.Filter.MinClient, args.Roles), nil
+	})
+	query := `{ user { field(filter: { role: ADMIN, minClient: "v1.2" }, roles: [GUEST]) } }`
+	checkTypedField(t, search, query, nil, map[string]any{"field": "admin {1 2} [guest]"})
+}
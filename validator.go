@@ -27,6 +27,13 @@ type ValidationResult struct {
  */
 
 func ValidateDocument(schema *Schema, astDoc *ast.Document, rules []ValidationRuleFn) (vr ValidationResult) {
+	return ValidateDocumentWithVariables(schema, astDoc, rules, nil)
+}
+
+// ValidateDocumentWithVariables validates a document like ValidateDocument,
+// giving the rules access to the variable values of the request, e.g. so that
+// MaxComplexityRule estimates the arguments given as variables.
+func ValidateDocumentWithVariables(schema *Schema, astDoc *ast.Document, rules []ValidationRuleFn, variableValues map[string]any) (vr ValidationResult) {
 	if len(rules) == 0 {
 		rules = SpecifiedRules
 	}
@@ -43,7 +50,9 @@ func ValidateDocument(schema *Schema, astDoc *ast.Document, rules []ValidationRu
 	typeInfo := NewTypeInfo(&TypeInfoConfig{
 		Schema: schema,
 	})
-	vr.Errors = VisitUsingRules(schema, typeInfo, astDoc, rules)
+	context := NewValidationContext(schema, astDoc, typeInfo)
+	context.variableValues = variableValues
+	vr.Errors = visitUsingRules(context, typeInfo, astDoc, rules)
 	if len(vr.Errors) == 0 {
 		vr.IsValid = true
 	}
@@ -57,8 +66,10 @@ func ValidateDocument(schema *Schema, astDoc *ast.Document, rules []ValidationRu
 // Had to expose it to unit test experimental customizable validation feature,
 // but not meant for public consumption
 func VisitUsingRules(schema *Schema, typeInfo *TypeInfo, astDoc *ast.Document, rules []ValidationRuleFn) []gqlerrors.FormattedError {
+	return visitUsingRules(NewValidationContext(schema, astDoc, typeInfo), typeInfo, astDoc, rules)
+}
 
-	context := NewValidationContext(schema, astDoc, typeInfo)
+func visitUsingRules(context *ValidationContext, typeInfo *TypeInfo, astDoc *ast.Document, rules []ValidationRuleFn) []gqlerrors.FormattedError {
 	visitors := []*visitor.VisitorOptions{}
 
 	for _, rule := range rules {
@@ -95,6 +106,7 @@ type ValidationContext struct {
 	recursiveVariableUsages        map[*ast.OperationDefinition][]*VariableUsage
 	recursivelyReferencedFragments map[*ast.OperationDefinition][]*ast.FragmentDefinition
 	fragmentSpreads                map[*ast.SelectionSet][]*ast.FragmentSpread
+	variableValues                 map[string]any
 }
 
 func NewValidationContext(schema *Schema, astDoc *ast.Document, typeInfo *TypeInfo) *ValidationContext {
@@ -121,6 +133,12 @@ func (ctx *ValidationContext) Errors() []gqlerrors.FormattedError {
 func (ctx *ValidationContext) Schema() *Schema {
 	return ctx.schema
 }
+
+// VariableValues returns the raw variable values of the request, or nil if
+// the document is validated without them.
+func (ctx *ValidationContext) VariableValues() map[string]any {
+	return ctx.variableValues
+}
 func (ctx *ValidationContext) Document() *ast.Document {
 	return ctx.astDoc
 }
@@ -177,6 +177,9 @@ func coerceValue(ttype Input, value any) (any, error) {
 				obj[name] = fieldValue
 			}
 		}
+		if ttype.IsOneOf() && len(obj) != 1 {
+			return nil, fmt.Errorf(`Exactly one key must be specified for OneOf type "%v".`, ttype.Name())
+		}
 		return obj, nil
 	case *Scalar:
 		return ttype.ParseValue(value)
@@ -290,6 +293,17 @@ func isValidInputValue(value any, ttype Input) (bool, []string) {
 				}
 			}
 		}
+
+		// Ensure exactly one non-null field is given to OneOf input objects.
+		if ttype.IsOneOf() {
+			if len(valueMapFieldNames) != 1 {
+				messagesReduce = append(messagesReduce,
+					fmt.Sprintf(`Exactly one key must be specified for OneOf type "%v".`, ttype.Name()))
+			} else if fieldName := valueMapFieldNames[0]; isNullish(valueMap[fieldName]) {
+				messagesReduce = append(messagesReduce,
+					fmt.Sprintf(`Field "%v.%v" must be non-null.`, ttype.Name(), fieldName))
+			}
+		}
 		return (len(messagesReduce) == 0), messagesReduce
 	case *Scalar:
 		if _, err := ttype.ParseValue(value); err != nil {
@@ -438,6 +452,9 @@ func valueFromAST(valueAST ast.Value, ttype Input, variables map[string]any) (an
 				obj[name] = value
 			}
 		}
+		if ttype.IsOneOf() && (len(fieldASTs) != 1 || len(obj) != 1) {
+			return nil, fmt.Errorf(`Exactly one key must be specified for OneOf type "%v".`, ttype.Name())
+		}
 		return obj, nil
 	case *Scalar:
 		return ttype.ParseLiteral(valueAST)